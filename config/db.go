@@ -25,7 +25,11 @@ func BuildDB() *gorm.DB {
 		DbName,
 	)
 
-	db, err := gorm.Open(postgres.Open(sqlCfg), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(sqlCfg), &gorm.Config{
+		// Report unique and foreign key violations as gorm.ErrDuplicatedKey
+		// and gorm.ErrForeignKeyViolated.
+		TranslateError: true,
+	})
 
 	if err != nil {
 		fmt.Println("Cannot connect to database postgres")
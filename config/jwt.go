@@ -0,0 +1,187 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"tablelink_project/server/utils"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const defaultSigningAlgorithm = "HS256"
+
+// KeyringFile describes the token signing keys in JWT_KEYRING_FILE.
+type KeyringFile struct {
+	Current string           `json:"current"`
+	Keys    []KeyringFileKey `json:"keys"`
+}
+
+// KeyringFileKey describes one signing key. Asymmetric keys are read from
+// File, HMAC keys take their secret from the SecretEnv environment variable.
+type KeyringFileKey struct {
+	ID        string `json:"kid"`
+	Algorithm string `json:"alg"`
+	File      string `json:"file"`
+	SecretEnv string `json:"secret_env"`
+	Retired   bool   `json:"retired"`
+}
+
+func BuildJWTConfig(keyring *utils.Keyring) utils.JWTConfig {
+	return utils.JWTConfig{
+		Keyring:              keyring,
+		Issuer:               os.Getenv("JWT_ISSUER"),
+		Audience:             os.Getenv("JWT_AUDIENCE"),
+		AccessTokenLifetime:  parseDuration("JWT_ACCESS_TOKEN_LIFETIME"),
+		RefreshTokenLifetime: parseDuration("JWT_REFRESH_TOKEN_LIFETIME"),
+	}
+}
+
+// EmbedRoleClaims reports whether JWT_EMBED_ROLE_CLAIMS asks for the user's
+// role to be embedded in access tokens.
+func EmbedRoleClaims() bool {
+	return parseBool("JWT_EMBED_ROLE_CLAIMS")
+}
+
+// BuildKeyring loads the signing keys from JWT_KEYRING_FILE, or a single
+// key described by JWT_ALGORITHM, JWT_KEY_ID, API_SECRET and
+// JWT_PRIVATE_KEY_FILE when no keyring file is configured.
+func BuildKeyring() *utils.Keyring {
+	keyring, err := loadKeyring()
+	if err != nil {
+		log.Fatalf("failed to load JWT signing keys: %v", err)
+	}
+
+	return keyring
+}
+
+// WatchKeyring reloads the keyring whenever the process receives SIGHUP, so
+// keys can be added, promoted and retired without a restart.
+func WatchKeyring(keyring *utils.Keyring) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		for range signals {
+			reloaded, err := loadKeyring()
+			if err != nil {
+				log.Printf("failed to reload JWT signing keys: %v", err)
+				continue
+			}
+			keyring.Replace(reloaded)
+			log.Println("Reloaded JWT signing keys")
+		}
+	}()
+}
+
+func loadKeyring() (*utils.Keyring, error) {
+	path := os.Getenv("JWT_KEYRING_FILE")
+	if path == "" {
+		key, err := buildSigningKey(KeyringFileKey{
+			ID:        os.Getenv("JWT_KEY_ID"),
+			Algorithm: os.Getenv("JWT_ALGORITHM"),
+			File:      os.Getenv("JWT_PRIVATE_KEY_FILE"),
+			SecretEnv: "API_SECRET",
+		})
+		if err != nil {
+			return nil, err
+		}
+		return utils.NewKeyring([]*utils.SigningKey{key}, key.ID)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := KeyringFile{}
+	err = json.Unmarshal(content, &file)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []*utils.SigningKey{}
+	retired := []string{}
+	for _, fileKey := range file.Keys {
+		key, err := buildSigningKey(fileKey)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %v", fileKey.ID, err)
+		}
+		keys = append(keys, key)
+		if fileKey.Retired {
+			retired = append(retired, key.ID)
+		}
+	}
+
+	keyring, err := utils.NewKeyring(keys, file.Current)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range retired {
+		err = keyring.Retire(id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return keyring, nil
+}
+
+// buildSigningKey creates an HMAC key for HS* algorithms and loads the PEM
+// private key for RS*, PS*, ES* and EdDSA.
+func buildSigningKey(fileKey KeyringFileKey) (*utils.SigningKey, error) {
+	alg := fileKey.Algorithm
+	if alg == "" {
+		alg = defaultSigningAlgorithm
+	}
+
+	method := jwt.GetSigningMethod(alg)
+	if method == nil || method == jwt.SigningMethodNone {
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", alg)
+	}
+
+	if _, ok := method.(*jwt.SigningMethodHMAC); ok {
+		keyID := fileKey.ID
+		if keyID == "" {
+			keyID = "default"
+		}
+		return utils.NewHMACKey(keyID, method, os.Getenv(fileKey.SecretEnv))
+	}
+
+	return utils.LoadSigningKeyFile(fileKey.ID, method, fileKey.File)
+}
+
+func parseDuration(key string) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", key, err)
+	}
+
+	return duration
+}
+
+func parseBool(key string) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return false
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", key, err)
+	}
+
+	return enabled
+}
@@ -0,0 +1,13 @@
+package config
+
+import "tablelink_project/server/service"
+
+func BuildLoginThrottleConfig() service.LoginThrottleConfig {
+	return service.LoginThrottleConfig{
+		MaxAccountFailures: parseInt("LOGIN_MAX_ACCOUNT_FAILURES"),
+		MaxIPFailures:      parseInt("LOGIN_MAX_IP_FAILURES"),
+		BaseLockout:        parseDuration("LOGIN_BASE_LOCKOUT"),
+		MaxLockout:         parseDuration("LOGIN_MAX_LOCKOUT"),
+		FailureWindow:      parseDuration("LOGIN_FAILURE_WINDOW"),
+	}
+}
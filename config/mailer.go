@@ -0,0 +1,40 @@
+package config
+
+import (
+	"log"
+	"os"
+
+	"tablelink_project/server/utils"
+)
+
+// BuildMailer returns the mailer selected by MAIL_DRIVER: "smtp", "file"
+// (writes to MAIL_FILE_DIR) or "memory", the default.
+func BuildMailer() utils.Mailer {
+	from := os.Getenv("MAIL_FROM")
+
+	switch driver := os.Getenv("MAIL_DRIVER"); driver {
+	case "smtp":
+		return utils.NewSMTPMailer(utils.SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		})
+	case "file":
+		dir := os.Getenv("MAIL_FILE_DIR")
+		if dir == "" {
+			dir = "mail"
+		}
+		mailer, err := utils.NewFileMailer(dir, from)
+		if err != nil {
+			log.Fatalf("failed to create file mailer: %v", err)
+		}
+		return mailer
+	case "", "memory":
+		return utils.NewMemoryMailer()
+	default:
+		log.Fatalf("unknown MAIL_DRIVER: %s", driver)
+		return nil
+	}
+}
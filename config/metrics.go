@@ -0,0 +1,16 @@
+package config
+
+import "os"
+
+// defaultMetricsAddress keeps the metrics listener on loopback so only
+// processes on the same host can scrape it.
+const defaultMetricsAddress = "127.0.0.1:9090"
+
+// MetricsAddress returns METRICS_ADDR, the address of the internal listener
+// serving /debug/vars, which is never exposed through the gateway.
+func MetricsAddress() string {
+	if address := os.Getenv("METRICS_ADDR"); address != "" {
+		return address
+	}
+	return defaultMetricsAddress
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"tablelink_project/server/service"
+	"tablelink_project/server/utils"
+)
+
+// BuildOIDCConfig reads the single sign-on settings. Single sign-on is
+// disabled, and ok is false, when OIDC_ISSUER is unset.
+func BuildOIDCConfig() (config utils.OIDCConfig, provisioning service.OIDCProvisioning, ok bool) {
+	config = utils.OIDCConfig{
+		Issuer:       os.Getenv("OIDC_ISSUER"),
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:       strings.Fields(os.Getenv("OIDC_SCOPES")),
+	}
+	if config.Issuer == "" {
+		return config, provisioning, false
+	}
+	if config.ClientID == "" || config.RedirectURL == "" {
+		log.Fatalf("OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required when OIDC_ISSUER is set")
+	}
+
+	provisioning.JIT = parseBool("OIDC_JIT_PROVISIONING")
+	provisioning.DefaultRoleID = uint(parseInt("OIDC_DEFAULT_ROLE_ID"))
+	if provisioning.JIT && provisioning.DefaultRoleID == 0 {
+		log.Fatalf("OIDC_DEFAULT_ROLE_ID is required when OIDC_JIT_PROVISIONING is enabled")
+	}
+
+	return config, provisioning, true
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+
+	"tablelink_project/server/service"
+)
+
+func BuildPasswordPolicy() service.PasswordPolicy {
+	var denylist map[string]struct{}
+	if path := os.Getenv("PASSWORD_DENYLIST_FILE"); path != "" {
+		var err error
+		denylist, err = service.LoadPasswordDenylist(path)
+		if err != nil {
+			log.Fatalf("failed to load password denylist: %v", err)
+		}
+	}
+
+	return service.NewRulePasswordPolicy(
+		parseInt("PASSWORD_MIN_LENGTH"),
+		parseInt("PASSWORD_MIN_CHARACTER_CLASSES"),
+		denylist,
+	)
+}
+
+func parseInt(key string) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", key, err)
+	}
+
+	return number
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"log"
+	"net"
+	"os"
+	"strings"
+)
+
+// defaultTrustedProxies trusts the gateway, which calls the gRPC server over
+// loopback.
+const defaultTrustedProxies = "127.0.0.1,::1"
+
+// BuildTrustedProxies parses TRUSTED_PROXIES, a comma-separated list of IP
+// addresses and CIDR ranges allowed to forward the client's address in
+// X-Forwarded-For.
+func BuildTrustedProxies() []*net.IPNet {
+	value := os.Getenv("TRUSTED_PROXIES")
+	if value == "" {
+		value = defaultTrustedProxies
+	}
+
+	networks := []*net.IPNet{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			log.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+		}
+		networks = append(networks, network)
+	}
+
+	return networks
+}
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS tokens (
+    id SERIAL PRIMARY KEY,
+    access_token_id VARCHAR(36) UNIQUE NOT NULL,
+    refresh_token_id VARCHAR(36) UNIQUE NOT NULL,
+    user_id INT NOT NULL,
+    expired_at TIMESTAMPTZ NOT NULL,
+    refresh_expired_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_tokens_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_tokens_user_id ON tokens (user_id);
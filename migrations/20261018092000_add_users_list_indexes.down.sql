@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_users_created_at_order;
+DROP INDEX IF EXISTS idx_users_last_access_order;
+DROP INDEX IF EXISTS idx_users_name_order;
+DROP INDEX IF EXISTS idx_users_name_trgm;
+DROP INDEX IF EXISTS idx_users_email_pattern;
+DROP INDEX IF EXISTS idx_users_role_id;
\ No newline at end of file
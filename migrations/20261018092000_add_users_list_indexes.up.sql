@@ -0,0 +1,8 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_users_role_id ON users (role_id, id);
+CREATE INDEX idx_users_email_pattern ON users (email text_pattern_ops);
+CREATE INDEX idx_users_name_trgm ON users USING gin (name gin_trgm_ops);
+CREATE INDEX idx_users_name_order ON users ((COALESCE(name, '')), id);
+CREATE INDEX idx_users_last_access_order ON users ((COALESCE(last_access, '0001-01-01'::timestamp)), id);
+CREATE INDEX idx_users_created_at_order ON users (created_at, id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tokens_session_id;
+ALTER TABLE tokens DROP COLUMN IF EXISTS session_id;
\ No newline at end of file
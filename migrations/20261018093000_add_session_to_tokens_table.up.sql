@@ -0,0 +1,3 @@
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS session_id VARCHAR(36) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_tokens_session_id ON tokens (session_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS user_totps;
+ALTER TABLE roles DROP COLUMN IF EXISTS mfa_required;
\ No newline at end of file
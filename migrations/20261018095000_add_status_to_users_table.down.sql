@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS invited_at;
+ALTER TABLE users DROP COLUMN IF EXISTS status;
\ No newline at end of file
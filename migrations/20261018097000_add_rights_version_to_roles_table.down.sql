@@ -0,0 +1 @@
+ALTER TABLE roles DROP COLUMN IF EXISTS rights_version;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS rights_version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,261 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.26.1
+// source: api/permission.proto
+
+package api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Action is the role right an RPC requires on its route.
+type Action int32
+
+const (
+	Action_ACTION_UNSPECIFIED Action = 0
+	Action_CREATE             Action = 1
+	Action_READ               Action = 2
+	Action_UPDATE             Action = 3
+	Action_DELETE             Action = 4
+)
+
+// Enum value maps for Action.
+var (
+	Action_name = map[int32]string{
+		0: "ACTION_UNSPECIFIED",
+		1: "CREATE",
+		2: "READ",
+		3: "UPDATE",
+		4: "DELETE",
+	}
+	Action_value = map[string]int32{
+		"ACTION_UNSPECIFIED": 0,
+		"CREATE":             1,
+		"READ":               2,
+		"UPDATE":             3,
+		"DELETE":             4,
+	}
+)
+
+func (x Action) Enum() *Action {
+	p := new(Action)
+	*p = x
+	return p
+}
+
+func (x Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_permission_proto_enumTypes[0].Descriptor()
+}
+
+func (Action) Type() protoreflect.EnumType {
+	return &file_api_permission_proto_enumTypes[0]
+}
+
+func (x Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Action.Descriptor instead.
+func (Action) EnumDescriptor() ([]byte, []int) {
+	return file_api_permission_proto_rawDescGZIP(), []int{0}
+}
+
+// Permission declares the role right required to call an RPC.
+// The route defaults to the path of the method's google.api.http rule and
+// the section defaults to the X-Link-Service metadata of the call.
+type Permission struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Action  Action                 `protobuf:"varint,1,opt,name=action,proto3,enum=tablelink.Action" json:"action,omitempty"`
+	Section string                 `protobuf:"bytes,2,opt,name=section,proto3" json:"section,omitempty"`
+	Route   string                 `protobuf:"bytes,3,opt,name=route,proto3" json:"route,omitempty"`
+	// Allows any authenticated caller without checking role rights.
+	AuthenticatedOnly bool `protobuf:"varint,4,opt,name=authenticated_only,json=authenticatedOnly,proto3" json:"authenticated_only,omitempty"`
+	// Denies the RPC to impersonation tokens, whatever the role's rights.
+	// Impersonation tokens are also denied every RPC that needs a role
+	// right other than READ, so this is only needed on authenticated_only
+	// RPCs and on reads that must stay out of reach.
+	Sensitive     bool `protobuf:"varint,5,opt,name=sensitive,proto3" json:"sensitive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Permission) Reset() {
+	*x = Permission{}
+	mi := &file_api_permission_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Permission) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Permission) ProtoMessage() {}
+
+func (x *Permission) ProtoReflect() protoreflect.Message {
+	mi := &file_api_permission_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Permission.ProtoReflect.Descriptor instead.
+func (*Permission) Descriptor() ([]byte, []int) {
+	return file_api_permission_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Permission) GetAction() Action {
+	if x != nil {
+		return x.Action
+	}
+	return Action_ACTION_UNSPECIFIED
+}
+
+func (x *Permission) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *Permission) GetRoute() string {
+	if x != nil {
+		return x.Route
+	}
+	return ""
+}
+
+func (x *Permission) GetAuthenticatedOnly() bool {
+	if x != nil {
+		return x.AuthenticatedOnly
+	}
+	return false
+}
+
+func (x *Permission) GetSensitive() bool {
+	if x != nil {
+		return x.Sensitive
+	}
+	return false
+}
+
+var file_api_permission_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*Permission)(nil),
+		Field:         50100,
+		Name:          "tablelink.permission",
+		Tag:           "bytes,50100,opt,name=permission",
+		Filename:      "api/permission.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// optional tablelink.Permission permission = 50100;
+	E_Permission = &file_api_permission_proto_extTypes[0]
+)
+
+var File_api_permission_proto protoreflect.FileDescriptor
+
+const file_api_permission_proto_rawDesc = "" +
+	"\n" +
+	"\x14api/permission.proto\x12\ttablelink\x1a google/protobuf/descriptor.proto\"\xb4\x01\n" +
+	"\n" +
+	"Permission\x12)\n" +
+	"\x06action\x18\x01 \x01(\x0e2\x11.tablelink.ActionR\x06action\x12\x18\n" +
+	"\asection\x18\x02 \x01(\tR\asection\x12\x14\n" +
+	"\x05route\x18\x03 \x01(\tR\x05route\x12-\n" +
+	"\x12authenticated_only\x18\x04 \x01(\bR\x11authenticatedOnly\x12\x1c\n" +
+	"\tsensitive\x18\x05 \x01(\bR\tsensitive*N\n" +
+	"\x06Action\x12\x16\n" +
+	"\x12ACTION_UNSPECIFIED\x10\x00\x12\n" +
+	"\n" +
+	"\x06CREATE\x10\x01\x12\b\n" +
+	"\x04READ\x10\x02\x12\n" +
+	"\n" +
+	"\x06UPDATE\x10\x03\x12\n" +
+	"\n" +
+	"\x06DELETE\x10\x04:W\n" +
+	"\n" +
+	"permission\x12\x1e.google.protobuf.MethodOptions\x18\xb4\x87\x03 \x01(\v2\x15.tablelink.PermissionR\n" +
+	"permissionB\x0fZ\rproto/api;apib\x06proto3"
+
+var (
+	file_api_permission_proto_rawDescOnce sync.Once
+	file_api_permission_proto_rawDescData []byte
+)
+
+func file_api_permission_proto_rawDescGZIP() []byte {
+	file_api_permission_proto_rawDescOnce.Do(func() {
+		file_api_permission_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_permission_proto_rawDesc), len(file_api_permission_proto_rawDesc)))
+	})
+	return file_api_permission_proto_rawDescData
+}
+
+var file_api_permission_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_permission_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_api_permission_proto_goTypes = []any{
+	(Action)(0),                        // 0: tablelink.Action
+	(*Permission)(nil),                 // 1: tablelink.Permission
+	(*descriptorpb.MethodOptions)(nil), // 2: google.protobuf.MethodOptions
+}
+var file_api_permission_proto_depIdxs = []int32{
+	0, // 0: tablelink.Permission.action:type_name -> tablelink.Action
+	2, // 1: tablelink.permission:extendee -> google.protobuf.MethodOptions
+	1, // 2: tablelink.permission:type_name -> tablelink.Permission
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	2, // [2:3] is the sub-list for extension type_name
+	1, // [1:2] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_api_permission_proto_init() }
+func file_api_permission_proto_init() {
+	if File_api_permission_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_permission_proto_rawDesc), len(file_api_permission_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_permission_proto_goTypes,
+		DependencyIndexes: file_api_permission_proto_depIdxs,
+		EnumInfos:         file_api_permission_proto_enumTypes,
+		MessageInfos:      file_api_permission_proto_msgTypes,
+		ExtensionInfos:    file_api_permission_proto_extTypes,
+	}.Build()
+	File_api_permission_proto = out.File
+	file_api_permission_proto_goTypes = nil
+	file_api_permission_proto_depIdxs = nil
+}
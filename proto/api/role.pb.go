@@ -0,0 +1,1187 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.26.1
+// source: api/role.proto
+
+package api
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetAllRolesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllRolesRequest) Reset() {
+	*x = GetAllRolesRequest{}
+	mi := &file_api_role_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllRolesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllRolesRequest) ProtoMessage() {}
+
+func (x *GetAllRolesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_role_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllRolesRequest.ProtoReflect.Descriptor instead.
+func (*GetAllRolesRequest) Descriptor() ([]byte, []int) {
+	return file_api_role_proto_rawDescGZIP(), []int{0}
+}
+
+type GetAllRolesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        bool                   `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Data          []*Role                `protobuf:"bytes,3,rep,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllRolesResponse) Reset() {
+	*x = GetAllRolesResponse{}
+	mi := &file_api_role_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllRolesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllRolesResponse) ProtoMessage() {}
+
+func (x *GetAllRolesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_role_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllRolesResponse.ProtoReflect.Descriptor instead.
+func (*GetAllRolesResponse) Descriptor() ([]byte, []int) {
+	return file_api_role_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetAllRolesResponse) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+func (x *GetAllRolesResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetAllRolesResponse) GetData() []*Role {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type GetRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoleId        uint32                 `protobuf:"varint,1,opt,name=role_id,json=roleId,proto3" json:"role_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoleRequest) Reset() {
+	*x = GetRoleRequest{}
+	mi := &file_api_role_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoleRequest) ProtoMessage() {}
+
+func (x *GetRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_role_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoleRequest.ProtoReflect.Descriptor instead.
+func (*GetRoleRequest) Descriptor() ([]byte, []int) {
+	return file_api_role_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetRoleRequest) GetRoleId() uint32 {
+	if x != nil {
+		return x.RoleId
+	}
+	return 0
+}
+
+type GetRoleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        bool                   `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Data          *Role                  `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoleResponse) Reset() {
+	*x = GetRoleResponse{}
+	mi := &file_api_role_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoleResponse) ProtoMessage() {}
+
+func (x *GetRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_role_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoleResponse.ProtoReflect.Descriptor instead.
+func (*GetRoleResponse) Descriptor() ([]byte, []int) {
+	return file_api_role_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetRoleResponse) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+func (x *GetRoleResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetRoleResponse) GetData() *Role {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type CreateRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MfaRequired   bool                   `protobuf:"varint,2,opt,name=mfa_required,json=mfaRequired,proto3" json:"mfa_required,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRoleRequest) Reset() {
+	*x = CreateRoleRequest{}
+	mi := &file_api_role_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRoleRequest) ProtoMessage() {}
+
+func (x *CreateRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_role_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRoleRequest.ProtoReflect.Descriptor instead.
+func (*CreateRoleRequest) Descriptor() ([]byte, []int) {
+	return file_api_role_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateRoleRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateRoleRequest) GetMfaRequired() bool {
+	if x != nil {
+		return x.MfaRequired
+	}
+	return false
+}
+
+type CreateRoleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        bool                   `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Data          *Role                  `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRoleResponse) Reset() {
+	*x = CreateRoleResponse{}
+	mi := &file_api_role_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRoleResponse) ProtoMessage() {}
+
+func (x *CreateRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_role_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRoleResponse.ProtoReflect.Descriptor instead.
+func (*CreateRoleResponse) Descriptor() ([]byte, []int) {
+	return file_api_role_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateRoleResponse) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+func (x *CreateRoleResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CreateRoleResponse) GetData() *Role {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type UpdateRoleRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	RoleId uint32                 `protobuf:"varint,1,opt,name=role_id,json=roleId,proto3" json:"role_id,omitempty"`
+	Name   string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Left unchanged when unset.
+	MfaRequired   *bool `protobuf:"varint,3,opt,name=mfa_required,json=mfaRequired,proto3,oneof" json:"mfa_required,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRoleRequest) Reset() {
+	*x = UpdateRoleRequest{}
+	mi := &file_api_role_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRoleRequest) ProtoMessage() {}
+
+func (x *UpdateRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_role_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRoleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRoleRequest) Descriptor() ([]byte, []int) {
+	return file_api_role_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateRoleRequest) GetRoleId() uint32 {
+	if x != nil {
+		return x.RoleId
+	}
+	return 0
+}
+
+func (x *UpdateRoleRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateRoleRequest) GetMfaRequired() bool {
+	if x != nil && x.MfaRequired != nil {
+		return *x.MfaRequired
+	}
+	return false
+}
+
+type UpdateRoleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        bool                   `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRoleResponse) Reset() {
+	*x = UpdateRoleResponse{}
+	mi := &file_api_role_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRoleResponse) ProtoMessage() {}
+
+func (x *UpdateRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_role_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRoleResponse.ProtoReflect.Descriptor instead.
+func (*UpdateRoleResponse) Descriptor() ([]byte, []int) {
+	return file_api_role_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateRoleResponse) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+func (x *UpdateRoleResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type DeleteRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoleId        uint32                 `protobuf:"varint,1,opt,name=role_id,json=roleId,proto3" json:"role_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRoleRequest) Reset() {
+	*x = DeleteRoleRequest{}
+	mi := &file_api_role_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRoleRequest) ProtoMessage() {}
+
+func (x *DeleteRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_role_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRoleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRoleRequest) Descriptor() ([]byte, []int) {
+	return file_api_role_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteRoleRequest) GetRoleId() uint32 {
+	if x != nil {
+		return x.RoleId
+	}
+	return 0
+}
+
+type DeleteRoleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        bool                   `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRoleResponse) Reset() {
+	*x = DeleteRoleResponse{}
+	mi := &file_api_role_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRoleResponse) ProtoMessage() {}
+
+func (x *DeleteRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_role_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRoleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRoleResponse) Descriptor() ([]byte, []int) {
+	return file_api_role_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteRoleResponse) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+func (x *DeleteRoleResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// GrantRoleRightRequest allows every flagged action on a section route.
+// Actions that are not flagged keep their current value.
+type GrantRoleRightRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoleId        uint32                 `protobuf:"varint,1,opt,name=role_id,json=roleId,proto3" json:"role_id,omitempty"`
+	Section       string                 `protobuf:"bytes,2,opt,name=section,proto3" json:"section,omitempty"`
+	Route         string                 `protobuf:"bytes,3,opt,name=route,proto3" json:"route,omitempty"`
+	RCreate       bool                   `protobuf:"varint,4,opt,name=r_create,json=rCreate,proto3" json:"r_create,omitempty"`
+	RRead         bool                   `protobuf:"varint,5,opt,name=r_read,json=rRead,proto3" json:"r_read,omitempty"`
+	RUpdate       bool                   `protobuf:"varint,6,opt,name=r_update,json=rUpdate,proto3" json:"r_update,omitempty"`
+	RDelete       bool                   `protobuf:"varint,7,opt,name=r_delete,json=rDelete,proto3" json:"r_delete,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GrantRoleRightRequest) Reset() {
+	*x = GrantRoleRightRequest{}
+	mi := &file_api_role_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrantRoleRightRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantRoleRightRequest) ProtoMessage() {}
+
+func (x *GrantRoleRightRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_role_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantRoleRightRequest.ProtoReflect.Descriptor instead.
+func (*GrantRoleRightRequest) Descriptor() ([]byte, []int) {
+	return file_api_role_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GrantRoleRightRequest) GetRoleId() uint32 {
+	if x != nil {
+		return x.RoleId
+	}
+	return 0
+}
+
+func (x *GrantRoleRightRequest) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *GrantRoleRightRequest) GetRoute() string {
+	if x != nil {
+		return x.Route
+	}
+	return ""
+}
+
+func (x *GrantRoleRightRequest) GetRCreate() bool {
+	if x != nil {
+		return x.RCreate
+	}
+	return false
+}
+
+func (x *GrantRoleRightRequest) GetRRead() bool {
+	if x != nil {
+		return x.RRead
+	}
+	return false
+}
+
+func (x *GrantRoleRightRequest) GetRUpdate() bool {
+	if x != nil {
+		return x.RUpdate
+	}
+	return false
+}
+
+func (x *GrantRoleRightRequest) GetRDelete() bool {
+	if x != nil {
+		return x.RDelete
+	}
+	return false
+}
+
+type GrantRoleRightResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        bool                   `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Data          *RoleRight             `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GrantRoleRightResponse) Reset() {
+	*x = GrantRoleRightResponse{}
+	mi := &file_api_role_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrantRoleRightResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantRoleRightResponse) ProtoMessage() {}
+
+func (x *GrantRoleRightResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_role_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantRoleRightResponse.ProtoReflect.Descriptor instead.
+func (*GrantRoleRightResponse) Descriptor() ([]byte, []int) {
+	return file_api_role_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GrantRoleRightResponse) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+func (x *GrantRoleRightResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GrantRoleRightResponse) GetData() *RoleRight {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// RevokeRoleRightRequest denies every flagged action on a section route.
+// The right is removed once no action is allowed anymore.
+type RevokeRoleRightRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoleId        uint32                 `protobuf:"varint,1,opt,name=role_id,json=roleId,proto3" json:"role_id,omitempty"`
+	Section       string                 `protobuf:"bytes,2,opt,name=section,proto3" json:"section,omitempty"`
+	Route         string                 `protobuf:"bytes,3,opt,name=route,proto3" json:"route,omitempty"`
+	RCreate       bool                   `protobuf:"varint,4,opt,name=r_create,json=rCreate,proto3" json:"r_create,omitempty"`
+	RRead         bool                   `protobuf:"varint,5,opt,name=r_read,json=rRead,proto3" json:"r_read,omitempty"`
+	RUpdate       bool                   `protobuf:"varint,6,opt,name=r_update,json=rUpdate,proto3" json:"r_update,omitempty"`
+	RDelete       bool                   `protobuf:"varint,7,opt,name=r_delete,json=rDelete,proto3" json:"r_delete,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeRoleRightRequest) Reset() {
+	*x = RevokeRoleRightRequest{}
+	mi := &file_api_role_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeRoleRightRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeRoleRightRequest) ProtoMessage() {}
+
+func (x *RevokeRoleRightRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_role_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeRoleRightRequest.ProtoReflect.Descriptor instead.
+func (*RevokeRoleRightRequest) Descriptor() ([]byte, []int) {
+	return file_api_role_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RevokeRoleRightRequest) GetRoleId() uint32 {
+	if x != nil {
+		return x.RoleId
+	}
+	return 0
+}
+
+func (x *RevokeRoleRightRequest) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *RevokeRoleRightRequest) GetRoute() string {
+	if x != nil {
+		return x.Route
+	}
+	return ""
+}
+
+func (x *RevokeRoleRightRequest) GetRCreate() bool {
+	if x != nil {
+		return x.RCreate
+	}
+	return false
+}
+
+func (x *RevokeRoleRightRequest) GetRRead() bool {
+	if x != nil {
+		return x.RRead
+	}
+	return false
+}
+
+func (x *RevokeRoleRightRequest) GetRUpdate() bool {
+	if x != nil {
+		return x.RUpdate
+	}
+	return false
+}
+
+func (x *RevokeRoleRightRequest) GetRDelete() bool {
+	if x != nil {
+		return x.RDelete
+	}
+	return false
+}
+
+type RevokeRoleRightResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        bool                   `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeRoleRightResponse) Reset() {
+	*x = RevokeRoleRightResponse{}
+	mi := &file_api_role_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeRoleRightResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeRoleRightResponse) ProtoMessage() {}
+
+func (x *RevokeRoleRightResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_role_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeRoleRightResponse.ProtoReflect.Descriptor instead.
+func (*RevokeRoleRightResponse) Descriptor() ([]byte, []int) {
+	return file_api_role_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RevokeRoleRightResponse) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+func (x *RevokeRoleRightResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type Role struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	RoleId     uint32                 `protobuf:"varint,1,opt,name=role_id,json=roleId,proto3" json:"role_id,omitempty"`
+	Name       string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	RoleRights []*RoleRight           `protobuf:"bytes,3,rep,name=role_rights,json=roleRights,proto3" json:"role_rights,omitempty"`
+	// Users of the role must pass MFA to log in.
+	MfaRequired bool `protobuf:"varint,4,opt,name=mfa_required,json=mfaRequired,proto3" json:"mfa_required,omitempty"`
+	// Incremented whenever the role's rights or name change.
+	RightsVersion int32 `protobuf:"varint,5,opt,name=rights_version,json=rightsVersion,proto3" json:"rights_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Role) Reset() {
+	*x = Role{}
+	mi := &file_api_role_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Role) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Role) ProtoMessage() {}
+
+func (x *Role) ProtoReflect() protoreflect.Message {
+	mi := &file_api_role_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Role.ProtoReflect.Descriptor instead.
+func (*Role) Descriptor() ([]byte, []int) {
+	return file_api_role_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Role) GetRoleId() uint32 {
+	if x != nil {
+		return x.RoleId
+	}
+	return 0
+}
+
+func (x *Role) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Role) GetRoleRights() []*RoleRight {
+	if x != nil {
+		return x.RoleRights
+	}
+	return nil
+}
+
+func (x *Role) GetMfaRequired() bool {
+	if x != nil {
+		return x.MfaRequired
+	}
+	return false
+}
+
+func (x *Role) GetRightsVersion() int32 {
+	if x != nil {
+		return x.RightsVersion
+	}
+	return 0
+}
+
+type RoleRight struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoleRightId   uint32                 `protobuf:"varint,1,opt,name=role_right_id,json=roleRightId,proto3" json:"role_right_id,omitempty"`
+	Section       string                 `protobuf:"bytes,2,opt,name=section,proto3" json:"section,omitempty"`
+	Route         string                 `protobuf:"bytes,3,opt,name=route,proto3" json:"route,omitempty"`
+	RCreate       bool                   `protobuf:"varint,4,opt,name=r_create,json=rCreate,proto3" json:"r_create,omitempty"`
+	RRead         bool                   `protobuf:"varint,5,opt,name=r_read,json=rRead,proto3" json:"r_read,omitempty"`
+	RUpdate       bool                   `protobuf:"varint,6,opt,name=r_update,json=rUpdate,proto3" json:"r_update,omitempty"`
+	RDelete       bool                   `protobuf:"varint,7,opt,name=r_delete,json=rDelete,proto3" json:"r_delete,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoleRight) Reset() {
+	*x = RoleRight{}
+	mi := &file_api_role_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoleRight) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoleRight) ProtoMessage() {}
+
+func (x *RoleRight) ProtoReflect() protoreflect.Message {
+	mi := &file_api_role_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoleRight.ProtoReflect.Descriptor instead.
+func (*RoleRight) Descriptor() ([]byte, []int) {
+	return file_api_role_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RoleRight) GetRoleRightId() uint32 {
+	if x != nil {
+		return x.RoleRightId
+	}
+	return 0
+}
+
+func (x *RoleRight) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *RoleRight) GetRoute() string {
+	if x != nil {
+		return x.Route
+	}
+	return ""
+}
+
+func (x *RoleRight) GetRCreate() bool {
+	if x != nil {
+		return x.RCreate
+	}
+	return false
+}
+
+func (x *RoleRight) GetRRead() bool {
+	if x != nil {
+		return x.RRead
+	}
+	return false
+}
+
+func (x *RoleRight) GetRUpdate() bool {
+	if x != nil {
+		return x.RUpdate
+	}
+	return false
+}
+
+func (x *RoleRight) GetRDelete() bool {
+	if x != nil {
+		return x.RDelete
+	}
+	return false
+}
+
+var File_api_role_proto protoreflect.FileDescriptor
+
+const file_api_role_proto_rawDesc = "" +
+	"\n" +
+	"\x0eapi/role.proto\x12\x04role\x1a\x1cgoogle/api/annotations.proto\x1a\x14api/permission.proto\"\x14\n" +
+	"\x12GetAllRolesRequest\"g\n" +
+	"\x13GetAllRolesResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\bR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1e\n" +
+	"\x04data\x18\x03 \x03(\v2\n" +
+	".role.RoleR\x04data\")\n" +
+	"\x0eGetRoleRequest\x12\x17\n" +
+	"\arole_id\x18\x01 \x01(\rR\x06roleId\"c\n" +
+	"\x0fGetRoleResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\bR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1e\n" +
+	"\x04data\x18\x03 \x01(\v2\n" +
+	".role.RoleR\x04data\"J\n" +
+	"\x11CreateRoleRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12!\n" +
+	"\fmfa_required\x18\x02 \x01(\bR\vmfaRequired\"f\n" +
+	"\x12CreateRoleResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\bR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1e\n" +
+	"\x04data\x18\x03 \x01(\v2\n" +
+	".role.RoleR\x04data\"y\n" +
+	"\x11UpdateRoleRequest\x12\x17\n" +
+	"\arole_id\x18\x01 \x01(\rR\x06roleId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12&\n" +
+	"\fmfa_required\x18\x03 \x01(\bH\x00R\vmfaRequired\x88\x01\x01B\x0f\n" +
+	"\r_mfa_required\"F\n" +
+	"\x12UpdateRoleResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\bR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\",\n" +
+	"\x11DeleteRoleRequest\x12\x17\n" +
+	"\arole_id\x18\x01 \x01(\rR\x06roleId\"F\n" +
+	"\x12DeleteRoleResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\bR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xc8\x01\n" +
+	"\x15GrantRoleRightRequest\x12\x17\n" +
+	"\arole_id\x18\x01 \x01(\rR\x06roleId\x12\x18\n" +
+	"\asection\x18\x02 \x01(\tR\asection\x12\x14\n" +
+	"\x05route\x18\x03 \x01(\tR\x05route\x12\x19\n" +
+	"\br_create\x18\x04 \x01(\bR\arCreate\x12\x15\n" +
+	"\x06r_read\x18\x05 \x01(\bR\x05rRead\x12\x19\n" +
+	"\br_update\x18\x06 \x01(\bR\arUpdate\x12\x19\n" +
+	"\br_delete\x18\a \x01(\bR\arDelete\"o\n" +
+	"\x16GrantRoleRightResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\bR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12#\n" +
+	"\x04data\x18\x03 \x01(\v2\x0f.role.RoleRightR\x04data\"\xc9\x01\n" +
+	"\x16RevokeRoleRightRequest\x12\x17\n" +
+	"\arole_id\x18\x01 \x01(\rR\x06roleId\x12\x18\n" +
+	"\asection\x18\x02 \x01(\tR\asection\x12\x14\n" +
+	"\x05route\x18\x03 \x01(\tR\x05route\x12\x19\n" +
+	"\br_create\x18\x04 \x01(\bR\arCreate\x12\x15\n" +
+	"\x06r_read\x18\x05 \x01(\bR\x05rRead\x12\x19\n" +
+	"\br_update\x18\x06 \x01(\bR\arUpdate\x12\x19\n" +
+	"\br_delete\x18\a \x01(\bR\arDelete\"K\n" +
+	"\x17RevokeRoleRightResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\bR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xaf\x01\n" +
+	"\x04Role\x12\x17\n" +
+	"\arole_id\x18\x01 \x01(\rR\x06roleId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x120\n" +
+	"\vrole_rights\x18\x03 \x03(\v2\x0f.role.RoleRightR\n" +
+	"roleRights\x12!\n" +
+	"\fmfa_required\x18\x04 \x01(\bR\vmfaRequired\x12%\n" +
+	"\x0erights_version\x18\x05 \x01(\x05R\rrightsVersion\"\xc7\x01\n" +
+	"\tRoleRight\x12\"\n" +
+	"\rrole_right_id\x18\x01 \x01(\rR\vroleRightId\x12\x18\n" +
+	"\asection\x18\x02 \x01(\tR\asection\x12\x14\n" +
+	"\x05route\x18\x03 \x01(\tR\x05route\x12\x19\n" +
+	"\br_create\x18\x04 \x01(\bR\arCreate\x12\x15\n" +
+	"\x06r_read\x18\x05 \x01(\bR\x05rRead\x12\x19\n" +
+	"\br_update\x18\x06 \x01(\bR\arUpdate\x12\x19\n" +
+	"\br_delete\x18\a \x01(\bR\arDelete2\xfc\x05\n" +
+	"\vRoleService\x12X\n" +
+	"\vGetAllRoles\x12\x18.role.GetAllRolesRequest\x1a\x19.role.GetAllRolesResponse\"\x14\xa2\xbb\x18\x02\b\x02\x82\xd3\xe4\x93\x02\b\x12\x06/roles\x12[\n" +
+	"\aGetRole\x12\x14.role.GetRoleRequest\x1a\x15.role.GetRoleResponse\"#\xa2\xbb\x18\x02\b\x02\x82\xd3\xe4\x93\x02\x17\x12\x15/roles/role/{role_id}\x12_\n" +
+	"\n" +
+	"CreateRole\x12\x17.role.CreateRoleRequest\x1a\x18.role.CreateRoleResponse\"\x1e\xa2\xbb\x18\x04\b\x01(\x01\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/roles/role\x12_\n" +
+	"\n" +
+	"UpdateRole\x12\x17.role.UpdateRoleRequest\x1a\x18.role.UpdateRoleResponse\"\x1e\xa2\xbb\x18\x04\b\x03(\x01\x82\xd3\xe4\x93\x02\x10:\x01*\x1a\v/roles/role\x12f\n" +
+	"\n" +
+	"DeleteRole\x12\x17.role.DeleteRoleRequest\x1a\x18.role.DeleteRoleResponse\"%\xa2\xbb\x18\x04\b\x04(\x01\x82\xd3\xe4\x93\x02\x17*\x15/roles/role/{role_id}\x12\x82\x01\n" +
+	"\x0eGrantRoleRight\x12\x1b.role.GrantRoleRightRequest\x1a\x1c.role.GrantRoleRightResponse\"5\xa2\xbb\x18\x04\b\x01(\x01\x82\xd3\xe4\x93\x02':\x01*\"\"/roles/role/{role_id}/rights/grant\x12\x86\x01\n" +
+	"\x0fRevokeRoleRight\x12\x1c.role.RevokeRoleRightRequest\x1a\x1d.role.RevokeRoleRightResponse\"6\xa2\xbb\x18\x04\b\x04(\x01\x82\xd3\xe4\x93\x02(:\x01*\"#/roles/role/{role_id}/rights/revokeB\x0fZ\rproto/api;apib\x06proto3"
+
+var (
+	file_api_role_proto_rawDescOnce sync.Once
+	file_api_role_proto_rawDescData []byte
+)
+
+func file_api_role_proto_rawDescGZIP() []byte {
+	file_api_role_proto_rawDescOnce.Do(func() {
+		file_api_role_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_role_proto_rawDesc), len(file_api_role_proto_rawDesc)))
+	})
+	return file_api_role_proto_rawDescData
+}
+
+var file_api_role_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_api_role_proto_goTypes = []any{
+	(*GetAllRolesRequest)(nil),      // 0: role.GetAllRolesRequest
+	(*GetAllRolesResponse)(nil),     // 1: role.GetAllRolesResponse
+	(*GetRoleRequest)(nil),          // 2: role.GetRoleRequest
+	(*GetRoleResponse)(nil),         // 3: role.GetRoleResponse
+	(*CreateRoleRequest)(nil),       // 4: role.CreateRoleRequest
+	(*CreateRoleResponse)(nil),      // 5: role.CreateRoleResponse
+	(*UpdateRoleRequest)(nil),       // 6: role.UpdateRoleRequest
+	(*UpdateRoleResponse)(nil),      // 7: role.UpdateRoleResponse
+	(*DeleteRoleRequest)(nil),       // 8: role.DeleteRoleRequest
+	(*DeleteRoleResponse)(nil),      // 9: role.DeleteRoleResponse
+	(*GrantRoleRightRequest)(nil),   // 10: role.GrantRoleRightRequest
+	(*GrantRoleRightResponse)(nil),  // 11: role.GrantRoleRightResponse
+	(*RevokeRoleRightRequest)(nil),  // 12: role.RevokeRoleRightRequest
+	(*RevokeRoleRightResponse)(nil), // 13: role.RevokeRoleRightResponse
+	(*Role)(nil),                    // 14: role.Role
+	(*RoleRight)(nil),               // 15: role.RoleRight
+}
+var file_api_role_proto_depIdxs = []int32{
+	14, // 0: role.GetAllRolesResponse.data:type_name -> role.Role
+	14, // 1: role.GetRoleResponse.data:type_name -> role.Role
+	14, // 2: role.CreateRoleResponse.data:type_name -> role.Role
+	15, // 3: role.GrantRoleRightResponse.data:type_name -> role.RoleRight
+	15, // 4: role.Role.role_rights:type_name -> role.RoleRight
+	0,  // 5: role.RoleService.GetAllRoles:input_type -> role.GetAllRolesRequest
+	2,  // 6: role.RoleService.GetRole:input_type -> role.GetRoleRequest
+	4,  // 7: role.RoleService.CreateRole:input_type -> role.CreateRoleRequest
+	6,  // 8: role.RoleService.UpdateRole:input_type -> role.UpdateRoleRequest
+	8,  // 9: role.RoleService.DeleteRole:input_type -> role.DeleteRoleRequest
+	10, // 10: role.RoleService.GrantRoleRight:input_type -> role.GrantRoleRightRequest
+	12, // 11: role.RoleService.RevokeRoleRight:input_type -> role.RevokeRoleRightRequest
+	1,  // 12: role.RoleService.GetAllRoles:output_type -> role.GetAllRolesResponse
+	3,  // 13: role.RoleService.GetRole:output_type -> role.GetRoleResponse
+	5,  // 14: role.RoleService.CreateRole:output_type -> role.CreateRoleResponse
+	7,  // 15: role.RoleService.UpdateRole:output_type -> role.UpdateRoleResponse
+	9,  // 16: role.RoleService.DeleteRole:output_type -> role.DeleteRoleResponse
+	11, // 17: role.RoleService.GrantRoleRight:output_type -> role.GrantRoleRightResponse
+	13, // 18: role.RoleService.RevokeRoleRight:output_type -> role.RevokeRoleRightResponse
+	12, // [12:19] is the sub-list for method output_type
+	5,  // [5:12] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_api_role_proto_init() }
+func file_api_role_proto_init() {
+	if File_api_role_proto != nil {
+		return
+	}
+	file_api_permission_proto_init()
+	file_api_role_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_role_proto_rawDesc), len(file_api_role_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_role_proto_goTypes,
+		DependencyIndexes: file_api_role_proto_depIdxs,
+		MessageInfos:      file_api_role_proto_msgTypes,
+	}.Build()
+	File_api_role_proto = out.File
+	file_api_role_proto_goTypes = nil
+	file_api_role_proto_depIdxs = nil
+}
@@ -3,73 +3,201 @@ package controller
 import (
 	"context"
 	"errors"
+	"sort"
+	"strconv"
+	"strings"
 	pb "tablelink_project/proto/api"
+	"tablelink_project/server/model"
 	"tablelink_project/server/service"
 	"tablelink_project/server/utils"
+	"time"
 
-	"github.com/go-redis/redis/v8"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type AuthController struct {
 	pb.UnimplementedAuthServiceServer
-	userService service.UserService
-	redisClient *redis.Client
+	userService          service.UserService
+	tokenService         service.TokenService
+	mfaService           service.MFAService
+	inviteService        service.InviteService
+	introspectionService service.IntrospectionService
+	tokenGenerator       utils.CredentialTokenGenerator
 }
 
-func NewAuthController(userService service.UserService, redis *redis.Client) *AuthController {
+func NewAuthController(
+	userService service.UserService,
+	tokenService service.TokenService,
+	mfaService service.MFAService,
+	inviteService service.InviteService,
+	introspectionService service.IntrospectionService,
+	tokenGenerator utils.CredentialTokenGenerator,
+) *AuthController {
 	return &AuthController{
-		userService: userService,
-		redisClient: redis,
+		userService:          userService,
+		tokenService:         tokenService,
+		mfaService:           mfaService,
+		inviteService:        inviteService,
+		introspectionService: introspectionService,
+		tokenGenerator:       tokenGenerator,
 	}
 }
 
 func (ac *AuthController) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
 	response := &pb.LoginResponse{}
-	existingToken, err := ac.redisClient.Get(ctx, req.Email).Result()
-	if err == nil {
-		response.Status = true
-		response.Message = "Login successful (from cache)"
-		response.AccessToken = existingToken
-		return response, nil
-	}
 
-	token, err := ac.userService.LoginCheck(req.Email, req.Password)
+	result, err := ac.userService.LoginCheck(ctx, req.Email, req.Password, sessionClient(ctx, req.Device))
+	if throttledErr := throttledStatus(ctx, err); throttledErr != nil {
+		response.Status = false
+		response.Message = "Login failed: " + err.Error()
+		return response, throttledErr
+	}
 	if err != nil {
 		response.Status = false
 		response.Message = "Login failed: " + err.Error()
 		return response, errors.New("username or password is incorrect")
 	}
 
-	err = ac.redisClient.Set(ctx, req.Email, token, utils.AccessTokenExpiredTime).Err()
-	if err != nil {
-		response.Status = false
-		response.Message = "Failed to save token in Redis: " + err.Error()
-		return response, errors.New("failed to save token in Redis")
+	return loginResponse(result), nil
+}
+
+// loginResponse describes a successful login, or the MFA step the user has
+// to pass first.
+func loginResponse(result *service.LoginResult) *pb.LoginResponse {
+	if result.Token == nil {
+		return &pb.LoginResponse{
+			Status:                true,
+			Message:               "MFA required",
+			MfaRequired:           true,
+			MfaChallengeToken:     result.MFAChallenge,
+			MfaChallengeExpiredAt: result.MFAChallengeExpiredAt.Format(time.RFC3339),
+			MfaEnrollmentRequired: result.MFAEnrollmentRequired,
+		}
 	}
 
 	return &pb.LoginResponse{
 		Status:       true,
 		Message:      "Login successful",
-		AccessToken:  token.AccessToken,
-		RefreshToken: token.RefreshToken,
-	}, nil
+		AccessToken:  result.Token.AccessToken,
+		RefreshToken: result.Token.RefreshToken,
+		SessionId:    result.Token.SessionID,
+	}
+}
+
+func (ac *AuthController) AcceptInvite(ctx context.Context, req *pb.AcceptInviteRequest) (*pb.AcceptInviteResponse, error) {
+	response := &pb.AcceptInviteResponse{}
+
+	err := ac.inviteService.AcceptInvite(ctx, req.InviteToken, req.Password)
+	if errors.Is(err, service.ErrInvalidInviteToken) {
+		response.Status = false
+		response.Message = "Invalid or expired invite token"
+		return response, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err != nil {
+		response.Status = false
+		response.Message = "Failed to accept invite"
+		if validationErr := validationStatus(err); validationErr != nil {
+			return response, validationErr
+		}
+		return response, status.Errorf(codes.Internal, "failed to accept invite: %v", err)
+	}
+
+	response.Status = true
+	response.Message = "Invite accepted"
+	return response, nil
+}
+
+func (ac *AuthController) EnrollTOTP(ctx context.Context, req *pb.EnrollTOTPRequest) (*pb.EnrollTOTPResponse, error) {
+	response := &pb.EnrollTOTPResponse{}
+	userID, ok := ctx.Value(utils.UserCtxKey).(uint)
+	if !ok {
+		response.Status = false
+		response.Message = "user not authenticated"
+		return response, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+
+	enrollment, err := ac.mfaService.EnrollTOTP(ctx, userID)
+	if errors.Is(err, service.ErrMFAAlreadyEnabled) {
+		response.Status = false
+		response.Message = "MFA is already enabled"
+		return response, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	if err != nil {
+		response.Status = false
+		response.Message = "Failed to enroll TOTP"
+		return response, status.Errorf(codes.Internal, "failed to enroll totp: %v", err)
+	}
+
+	response.Status = true
+	response.Message = "Scan the URI and verify a code to enable MFA"
+	response.Secret = enrollment.Secret
+	response.OtpauthUri = enrollment.URI
+	response.RecoveryCodes = enrollment.RecoveryCodes
+	return response, nil
+}
+
+func (ac *AuthController) VerifyTOTP(ctx context.Context, req *pb.VerifyTOTPRequest) (*pb.VerifyTOTPResponse, error) {
+	response := &pb.VerifyTOTPResponse{}
+	userID, ok := ctx.Value(utils.UserCtxKey).(uint)
+	if !ok {
+		response.Status = false
+		response.Message = "user not authenticated"
+		return response, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+
+	challenge, isChallenge := ctx.Value(utils.MFAChallengeCtxKey).(*utils.MFAChallengeClaim)
+	if !isChallenge {
+		err := ac.mfaService.VerifyTOTP(ctx, userID, req.Code)
+		if err != nil {
+			response.Status = false
+			response.Message = "Verification failed: " + err.Error()
+			return response, mfaError(err)
+		}
+
+		response.Status = true
+		response.Message = "MFA enabled"
+		return response, nil
+	}
+
+	token, err := ac.userService.CompleteMFALogin(ctx, challenge, req.Code, sessionClient(ctx, ""))
+	if throttledErr := throttledStatus(ctx, err); throttledErr != nil {
+		response.Status = false
+		response.Message = "Login failed: " + err.Error()
+		return response, throttledErr
+	}
+	if err != nil {
+		response.Status = false
+		response.Message = "Login failed: " + err.Error()
+		return response, mfaError(err)
+	}
+
+	response.Status = true
+	response.Message = "Login successful"
+	response.AccessToken = token.AccessToken
+	response.RefreshToken = token.RefreshToken
+	response.SessionId = token.SessionID
+	return response, nil
 }
 
 func (ac *AuthController) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
 	response := &pb.RefreshTokenResponse{}
 
 	// Validate the refresh token
-	token, err := utils.ValidateRefreshToken(req.RefreshToken)
+	token, err := ac.tokenGenerator.ParseRefreshToken(req.RefreshToken)
 	if err != nil {
 		response.Status = false
 		response.Message = "Invalid refresh token"
 		return response, status.Errorf(codes.Unauthenticated, "invalid refresh token: %v", err)
 	}
 
-	// Generate a new access token
-	accessToken, err := utils.GenerateToken(token.UserID)
+	// Rotate the refresh token, retiring the presented one
+	newToken, err := ac.tokenService.RotateToken(ctx, token)
+	if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) {
+		response.Status = false
+		response.Message = "Invalid refresh token"
+		return response, status.Errorf(codes.Unauthenticated, "%v", err)
+	}
 	if err != nil {
 		response.Status = false
 		response.Message = "Failed to generate access token"
@@ -78,7 +206,261 @@ func (ac *AuthController) RefreshToken(ctx context.Context, req *pb.RefreshToken
 
 	response.Status = true
 	response.Message = "Token refreshed successfully"
-	response.AccessToken = accessToken.AccessToken
-	response.RefreshToken = accessToken.RefreshToken
+	response.AccessToken = newToken.AccessToken
+	response.RefreshToken = newToken.RefreshToken
+	return response, nil
+}
+
+func (ac *AuthController) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	response := &pb.LogoutResponse{}
+
+	claim, err := ac.tokenGenerator.ParseToken(req.AccessToken)
+	if err != nil {
+		response.Status = false
+		response.Message = "Invalid access token"
+		return response, status.Errorf(codes.Unauthenticated, "invalid access token: %v", err)
+	}
+
+	err = ac.tokenService.RevokeToken(ctx, claim)
+	if err != nil {
+		response.Status = false
+		response.Message = "Failed to revoke token"
+		return response, status.Errorf(codes.Internal, "failed to revoke token: %v", err)
+	}
+
+	response.Status = true
+	response.Message = "Logout successful"
 	return response, nil
 }
+
+func (ac *AuthController) CompletePasswordReset(ctx context.Context, req *pb.CompletePasswordResetRequest) (*pb.CompletePasswordResetResponse, error) {
+	response := &pb.CompletePasswordResetResponse{}
+
+	err := ac.userService.CompletePasswordReset(ctx, req.ResetToken, req.NewPassword)
+	if errors.Is(err, service.ErrInvalidResetToken) {
+		response.Status = false
+		response.Message = "Invalid or expired reset token"
+		return response, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err != nil {
+		response.Status = false
+		response.Message = "Failed to reset password"
+		if validationErr := validationStatus(err); validationErr != nil {
+			return response, validationErr
+		}
+		return response, status.Errorf(codes.Internal, "failed to reset password: %v", err)
+	}
+
+	response.Status = true
+	response.Message = "Password reset successful"
+	return response, nil
+}
+
+func (ac *AuthController) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	response := &pb.ListSessionsResponse{}
+	userID, ok := ctx.Value(utils.UserCtxKey).(uint)
+	if !ok {
+		response.Status = false
+		response.Message = "user not authenticated"
+		return response, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+	currentSessionID, _ := ctx.Value(utils.SessionCtxKey).(string)
+
+	sessions, err := ac.tokenService.GetSessions(ctx, userID)
+	if err != nil {
+		response.Status = false
+		response.Message = "Failed to list sessions"
+		return response, status.Errorf(codes.Internal, "failed to list sessions: %v", err)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	response.Status = true
+	response.Message = "success"
+	for _, session := range sessions {
+		response.Data = append(response.Data, toSessionPb(session, currentSessionID))
+	}
+	return response, nil
+}
+
+func (ac *AuthController) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	response := &pb.RevokeSessionResponse{}
+	userID, ok := ctx.Value(utils.UserCtxKey).(uint)
+	if !ok {
+		response.Status = false
+		response.Message = "user not authenticated"
+		return response, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+
+	err := ac.tokenService.RevokeSession(ctx, userID, req.SessionId)
+	if errors.Is(err, service.ErrSessionNotFound) {
+		response.Status = false
+		response.Message = "Session not found"
+		return response, status.Errorf(codes.NotFound, "%v", err)
+	}
+	if err != nil {
+		response.Status = false
+		response.Message = "Failed to revoke session"
+		return response, status.Errorf(codes.Internal, "failed to revoke session: %v", err)
+	}
+
+	response.Status = true
+	response.Message = "Session revoked"
+	return response, nil
+}
+
+func (ac *AuthController) RevokeAllSessions(ctx context.Context, req *pb.RevokeAllSessionsRequest) (*pb.RevokeAllSessionsResponse, error) {
+	response := &pb.RevokeAllSessionsResponse{}
+	userID, ok := ctx.Value(utils.UserCtxKey).(uint)
+	if !ok {
+		response.Status = false
+		response.Message = "user not authenticated"
+		return response, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+
+	var err error
+	if currentSessionID, _ := ctx.Value(utils.SessionCtxKey).(string); req.KeepCurrent && currentSessionID != "" {
+		err = ac.tokenService.RevokeOtherSessions(ctx, userID, currentSessionID)
+	} else {
+		err = ac.tokenService.RevokeUserTokens(ctx, int(userID))
+	}
+	if err != nil {
+		response.Status = false
+		response.Message = "Failed to revoke sessions"
+		return response, status.Errorf(codes.Internal, "failed to revoke sessions: %v", err)
+	}
+
+	response.Status = true
+	response.Message = "Sessions revoked"
+	return response, nil
+}
+
+// Introspect answers with only active unset for tokens that are not active,
+// so callers cannot tell expired, revoked and forged tokens apart.
+func (ac *AuthController) Introspect(ctx context.Context, req *pb.IntrospectRequest) (*pb.IntrospectResponse, error) {
+	if req.Token == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "token is required")
+	}
+
+	introspection, err := ac.introspectionService.Introspect(ctx, req.Token, req.TokenTypeHint)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to introspect token: %v", err)
+	}
+	if !introspection.Active {
+		return &pb.IntrospectResponse{}, nil
+	}
+
+	response := &pb.IntrospectResponse{
+		Active:    true,
+		Scope:     strings.Join(introspection.Scopes, " "),
+		ClientId:  introspection.ClientID,
+		Username:  introspection.Username,
+		TokenType: introspection.TokenType,
+		Exp:       introspection.ExpiresAt,
+		Iat:       introspection.IssuedAt,
+		Sub:       introspection.Subject,
+		Aud:       introspection.Audience,
+		Iss:       introspection.Issuer,
+		Jti:       introspection.TokenID,
+		Role:      introspection.Role,
+		RoleId:    uint32(introspection.RoleID),
+		Sid:       introspection.SessionID,
+	}
+	if introspection.Actor != "" {
+		response.Act = &pb.Actor{Sub: introspection.Actor}
+	}
+	return response, nil
+}
+
+func (ac *AuthController) UserInfo(ctx context.Context, req *pb.UserInfoRequest) (*pb.UserInfoResponse, error) {
+	userID, ok := ctx.Value(utils.UserCtxKey).(uint)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+
+	user, err := ac.userService.GetUserByID(int(userID))
+	if err != nil {
+		return nil, userError(err)
+	}
+
+	return &pb.UserInfoResponse{
+		Sub:   strconv.FormatUint(uint64(user.ID), 10),
+		Name:  user.Name,
+		Email: user.Email,
+		Role:  user.Role.Name,
+	}, nil
+}
+
+func (ac *AuthController) Impersonate(ctx context.Context, req *pb.ImpersonateRequest) (*pb.ImpersonateResponse, error) {
+	response := &pb.ImpersonateResponse{}
+	actorID, ok := ctx.Value(utils.UserCtxKey).(uint)
+	if !ok {
+		response.Status = false
+		response.Message = "user not authenticated"
+		return response, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+	sessionID, _ := ctx.Value(utils.SessionCtxKey).(string)
+
+	accessToken, expiredAt, err := ac.userService.Impersonate(ctx, actorID, sessionID, uint(req.UserId), req.Reason)
+	if err != nil {
+		response.Status = false
+		response.Message = "Impersonation failed: " + err.Error()
+		if validationErr := validationStatus(err); validationErr != nil {
+			return response, validationErr
+		}
+		if errors.Is(err, service.ErrImpersonationDenied) {
+			return response, status.Errorf(codes.PermissionDenied, "%v", err)
+		}
+		if errors.Is(err, service.ErrImpersonationWithoutSession) {
+			return response, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		return response, userError(err)
+	}
+
+	response.Status = true
+	response.Message = "Impersonation started"
+	response.AccessToken = accessToken
+	response.ExpiredAt = expiredAt.Format(time.RFC3339)
+	return response, nil
+}
+
+// sessionClient describes the caller for a new session, naming the device
+// after the user agent unless the client named it.
+func sessionClient(ctx context.Context, device string) service.SessionClient {
+	client := service.SessionClient{
+		Device:    device,
+		UserAgent: utils.UserAgent(ctx),
+		IP:        utils.ClientIP(ctx),
+	}
+	if client.Device == "" {
+		client.Device = client.UserAgent
+	}
+	return client
+}
+
+func mfaError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidMFACode):
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	case errors.Is(err, service.ErrMFANotEnrolled):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	case errors.Is(err, service.ErrUserNotActive):
+		return status.Errorf(codes.PermissionDenied, "%v", err)
+	}
+	return status.Errorf(codes.Internal, "error: %v", err)
+}
+
+func toSessionPb(session model.Session, currentSessionID string) *pb.Session {
+	return &pb.Session{
+		SessionId:  session.ID,
+		Device:     session.Device,
+		UserAgent:  session.UserAgent,
+		IpAddress:  session.IP,
+		CreatedAt:  session.IssuedAt.Format(time.RFC3339),
+		LastSeenAt: session.LastSeenAt.Format(time.RFC3339),
+		ExpiredAt:  session.ExpiredAt.Format(time.RFC3339),
+		Current:    session.ID == currentSessionID,
+	}
+}
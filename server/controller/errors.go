@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"tablelink_project/server/service"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// validationStatus converts a service.ValidationError into an
+// InvalidArgument status carrying a BadRequest detail per violated field.
+// It returns nil for any other error.
+func validationStatus(err error) error {
+	var validationErr *service.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, violation := range validationErr.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Reason:      violation.Reason,
+			Description: violation.Description,
+		})
+	}
+
+	st, detailErr := status.New(codes.InvalidArgument, validationErr.Error()).WithDetails(badRequest)
+	if detailErr != nil {
+		return status.Error(codes.InvalidArgument, validationErr.Error())
+	}
+	return st.Err()
+}
+
+// throttledStatus converts a service.ThrottledError into a
+// ResourceExhausted status carrying a RetryInfo detail, and sends the delay
+// as retry-after response metadata. It returns nil for any other error.
+func throttledStatus(ctx context.Context, err error) error {
+	var throttledErr *service.ThrottledError
+	if !errors.As(err, &throttledErr) {
+		return nil
+	}
+
+	retryAfter := throttledErr.RetryAfterSeconds()
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.FormatInt(retryAfter, 10)))
+
+	retryInfo := &errdetails.RetryInfo{
+		RetryDelay: durationpb.New(time.Duration(retryAfter) * time.Second),
+	}
+	st, detailErr := status.New(codes.ResourceExhausted, throttledErr.Error()).WithDetails(retryInfo)
+	if detailErr != nil {
+		return status.Error(codes.ResourceExhausted, throttledErr.Error())
+	}
+	return st.Err()
+}
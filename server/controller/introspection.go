@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	pb "tablelink_project/proto/api"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// introspectionJSON is the RFC 7662 introspection response. Unlike the
+// gateway's JSON, empty fields are omitted and times are numbers.
+type introspectionJSON struct {
+	Active    bool       `json:"active"`
+	Scope     string     `json:"scope,omitempty"`
+	ClientID  string     `json:"client_id,omitempty"`
+	Username  string     `json:"username,omitempty"`
+	TokenType string     `json:"token_type,omitempty"`
+	Exp       int64      `json:"exp,omitempty"`
+	Iat       int64      `json:"iat,omitempty"`
+	Sub       string     `json:"sub,omitempty"`
+	Aud       string     `json:"aud,omitempty"`
+	Iss       string     `json:"iss,omitempty"`
+	Jti       string     `json:"jti,omitempty"`
+	Role      string     `json:"role,omitempty"`
+	RoleID    uint32     `json:"role_id,omitempty"`
+	Sid       string     `json:"sid,omitempty"`
+	Act       *actorJSON `json:"act,omitempty"`
+}
+
+type actorJSON struct {
+	Sub string `json:"sub"`
+}
+
+// NewIntrospectionHandler serves RFC 7662 form-encoded introspection
+// requests. It calls the Introspect RPC with the caller's credentials, so
+// callers are authenticated and authorized like any other call.
+func NewIntrospectionHandler(mux *runtime.ServeMux, authClient pb.AuthServiceClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		err := r.ParseForm()
+		if err != nil {
+			writeHTTPError(mux, w, r, status.Errorf(codes.InvalidArgument, "invalid form: %v", err))
+			return
+		}
+
+		ctx, err := runtime.AnnotateContext(r.Context(), mux, r, pb.AuthService_Introspect_FullMethodName, runtime.WithHTTPPathPattern("/oauth/introspect"))
+		if err != nil {
+			writeHTTPError(mux, w, r, err)
+			return
+		}
+
+		response, err := authClient.Introspect(ctx, &pb.IntrospectRequest{
+			Token:         r.PostForm.Get("token"),
+			TokenTypeHint: r.PostForm.Get("token_type_hint"),
+		})
+		if err != nil {
+			writeHTTPError(mux, w, r, err)
+			return
+		}
+
+		introspection := introspectionJSON{
+			Active:    response.Active,
+			Scope:     response.Scope,
+			ClientID:  response.ClientId,
+			Username:  response.Username,
+			TokenType: response.TokenType,
+			Exp:       response.Exp,
+			Iat:       response.Iat,
+			Sub:       response.Sub,
+			Aud:       response.Aud,
+			Iss:       response.Iss,
+			Jti:       response.Jti,
+			Role:      response.Role,
+			RoleID:    response.RoleId,
+			Sid:       response.Sid,
+		}
+		if response.Act != nil {
+			introspection.Act = &actorJSON{Sub: response.Act.Sub}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(introspection)
+	}
+}
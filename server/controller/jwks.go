@@ -0,0 +1,19 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"tablelink_project/server/utils"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// NewJWKSHandler serves the public token signing keys so other services can
+// verify access tokens without holding our secret.
+func NewJWKSHandler(tokenGenerator utils.CredentialTokenGenerator) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		_ = json.NewEncoder(w).Encode(tokenGenerator.JWKS())
+	}
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// NewMetricsHandler serves the named expvar variables as a JSON object, like
+// expvar.Handler but without the command line, memory statistics and any
+// other variable published by the process.
+func NewMetricsHandler(names ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		fmt.Fprint(w, "{")
+		first := true
+		for _, name := range names {
+			value := expvar.Get(name)
+			if value == nil {
+				continue
+			}
+			if !first {
+				fmt.Fprint(w, ",")
+			}
+			first = false
+			fmt.Fprintf(w, "\n%s: %s", strconv.Quote(name), value)
+		}
+		fmt.Fprint(w, "\n}\n")
+	})
+}
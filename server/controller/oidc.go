@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"tablelink_project/server/service"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewOIDCStartHandler redirects the user to the identity provider to sign
+// in.
+func NewOIDCStartHandler(mux *runtime.ServeMux, oidcService service.OIDCService) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		authURL, err := oidcService.StartLogin(r.Context())
+		if err != nil {
+			writeHTTPError(mux, w, r, status.Errorf(codes.Unavailable, "failed to start oidc login: %v", err))
+			return
+		}
+
+		http.Redirect(w, r, authURL, http.StatusFound)
+	}
+}
+
+// NewOIDCCallbackHandler completes the login the identity provider
+// redirected back from and responds like the Login RPC.
+func NewOIDCCallbackHandler(mux *runtime.ServeMux, oidcService service.OIDCService) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		query := r.URL.Query()
+		if providerErr := query.Get("error"); providerErr != "" {
+			writeHTTPError(mux, w, r, status.Errorf(codes.Unauthenticated, "identity provider returned %s: %s", providerErr, query.Get("error_description")))
+			return
+		}
+		if query.Get("state") == "" || query.Get("code") == "" {
+			writeHTTPError(mux, w, r, status.Errorf(codes.InvalidArgument, "state and code are required"))
+			return
+		}
+
+		result, err := oidcService.CompleteLogin(r.Context(), query.Get("state"), query.Get("code"), httpSessionClient(r))
+		if throttledErr := throttledStatus(r.Context(), err); throttledErr != nil {
+			// There is no gRPC stream here to carry retry-after metadata.
+			var retryErr *service.ThrottledError
+			if errors.As(err, &retryErr) {
+				w.Header().Set("Retry-After", strconv.FormatInt(retryErr.RetryAfterSeconds(), 10))
+			}
+			writeHTTPError(mux, w, r, throttledErr)
+			return
+		}
+		if err != nil {
+			writeHTTPError(mux, w, r, oidcError(err))
+			return
+		}
+
+		_, marshaler := runtime.MarshalerForRequest(mux, r)
+		body, err := marshaler.Marshal(loginResponse(result))
+		if err != nil {
+			writeHTTPError(mux, w, r, status.Errorf(codes.Internal, "failed to marshal response: %v", err))
+			return
+		}
+
+		w.Header().Set("Content-Type", marshaler.ContentType(result))
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write(body)
+	}
+}
+
+func oidcError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidOIDCState):
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	case errors.Is(err, service.ErrOIDCIdentityDenied):
+		return status.Errorf(codes.Unauthenticated, "%v", err)
+	case errors.Is(err, service.ErrOIDCUserNotFound), errors.Is(err, service.ErrUserNotActive):
+		return status.Errorf(codes.PermissionDenied, "%v", err)
+	default:
+		return status.Errorf(codes.Internal, "oidc login failed: %v", err)
+	}
+}
+
+// httpSessionClient describes the client of a request served directly by
+// the gateway mux rather than forwarded to the gRPC server.
+func httpSessionClient(r *http.Request) service.SessionClient {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return service.SessionClient{
+		Device:    r.UserAgent(),
+		UserAgent: r.UserAgent(),
+		IP:        ip,
+	}
+}
+
+func writeHTTPError(mux *runtime.ServeMux, w http.ResponseWriter, r *http.Request, err error) {
+	_, marshaler := runtime.MarshalerForRequest(mux, r)
+	runtime.HTTPError(r.Context(), mux, marshaler, w, r, err)
+}
@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	pb "tablelink_project/proto/api"
+	"tablelink_project/server/model"
+	"tablelink_project/server/service"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type RoleController struct {
+	pb.UnimplementedRoleServiceServer
+	roleService service.RoleService
+}
+
+func NewRoleController(roleService service.RoleService) *RoleController {
+	return &RoleController{
+		roleService: roleService,
+	}
+}
+
+func (rc *RoleController) GetAllRoles(ctx context.Context, req *pb.GetAllRolesRequest) (*pb.GetAllRolesResponse, error) {
+	response := &pb.GetAllRolesResponse{}
+	roles, err := rc.roleService.GetAllRoles()
+	if err != nil {
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, status.Errorf(codes.Internal, "error: %v", err.Error())
+	}
+
+	for _, role := range roles {
+		response.Data = append(response.Data, toRolePb(role))
+	}
+	response.Status = true
+	response.Message = "success"
+	return response, nil
+}
+
+func (rc *RoleController) GetRole(ctx context.Context, req *pb.GetRoleRequest) (*pb.GetRoleResponse, error) {
+	response := &pb.GetRoleResponse{}
+	role, err := rc.roleService.GetRoleByID(int(req.RoleId))
+	if err != nil {
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, roleError(err)
+	}
+
+	response.Status = true
+	response.Message = "success"
+	response.Data = toRolePb(*role)
+	return response, nil
+}
+
+func (rc *RoleController) CreateRole(ctx context.Context, req *pb.CreateRoleRequest) (*pb.CreateRoleResponse, error) {
+	response := &pb.CreateRoleResponse{}
+	if req.Name == "" {
+		response.Status = false
+		response.Message = "error: name is required"
+		return response, status.Errorf(codes.InvalidArgument, "name is required")
+	}
+
+	role := &model.Role{
+		Name:        req.Name,
+		MFARequired: req.MfaRequired,
+	}
+	err := rc.roleService.CreateRole(role)
+	if err != nil {
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, roleError(err)
+	}
+
+	response.Status = true
+	response.Message = "success"
+	response.Data = toRolePb(*role)
+	return response, nil
+}
+
+func (rc *RoleController) UpdateRole(ctx context.Context, req *pb.UpdateRoleRequest) (*pb.UpdateRoleResponse, error) {
+	response := &pb.UpdateRoleResponse{}
+	if req.Name == "" {
+		response.Status = false
+		response.Message = "error: name is required"
+		return response, status.Errorf(codes.InvalidArgument, "name is required")
+	}
+
+	err := rc.roleService.UpdateRole(ctx, &model.Role{
+		ID:   uint(req.RoleId),
+		Name: req.Name,
+	}, req.MfaRequired)
+	if err != nil {
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, roleError(err)
+	}
+
+	response.Status = true
+	response.Message = "success"
+	return response, nil
+}
+
+func (rc *RoleController) DeleteRole(ctx context.Context, req *pb.DeleteRoleRequest) (*pb.DeleteRoleResponse, error) {
+	response := &pb.DeleteRoleResponse{}
+	err := rc.roleService.DeleteRole(ctx, int(req.RoleId))
+	if err != nil {
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, roleError(err)
+	}
+
+	response.Status = true
+	response.Message = "success"
+	return response, nil
+}
+
+func (rc *RoleController) GrantRoleRight(ctx context.Context, req *pb.GrantRoleRightRequest) (*pb.GrantRoleRightResponse, error) {
+	response := &pb.GrantRoleRightResponse{}
+	if req.Section == "" || req.Route == "" {
+		response.Status = false
+		response.Message = "error: section and route are required"
+		return response, status.Errorf(codes.InvalidArgument, "section and route are required")
+	}
+
+	roleRight, err := rc.roleService.GrantRoleRight(ctx, model.RoleRight{
+		RoleID:  uint(req.RoleId),
+		Section: req.Section,
+		Route:   req.Route,
+		RCreate: boolToRight(req.RCreate),
+		RRead:   boolToRight(req.RRead),
+		RUpdate: boolToRight(req.RUpdate),
+		RDelete: boolToRight(req.RDelete),
+	})
+	if err != nil {
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, roleError(err)
+	}
+
+	response.Status = true
+	response.Message = "success"
+	response.Data = toRoleRightPb(*roleRight)
+	return response, nil
+}
+
+func (rc *RoleController) RevokeRoleRight(ctx context.Context, req *pb.RevokeRoleRightRequest) (*pb.RevokeRoleRightResponse, error) {
+	response := &pb.RevokeRoleRightResponse{}
+	if req.Section == "" || req.Route == "" {
+		response.Status = false
+		response.Message = "error: section and route are required"
+		return response, status.Errorf(codes.InvalidArgument, "section and route are required")
+	}
+
+	err := rc.roleService.RevokeRoleRight(ctx, model.RoleRight{
+		RoleID:  uint(req.RoleId),
+		Section: req.Section,
+		Route:   req.Route,
+		RCreate: boolToRight(req.RCreate),
+		RRead:   boolToRight(req.RRead),
+		RUpdate: boolToRight(req.RUpdate),
+		RDelete: boolToRight(req.RDelete),
+	})
+	if err != nil {
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, roleError(err)
+	}
+
+	response.Status = true
+	response.Message = "success"
+	return response, nil
+}
+
+func roleError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status.Errorf(codes.NotFound, "error: %v", err.Error())
+	}
+	if errors.Is(err, service.ErrRoleNameTaken) {
+		return status.Errorf(codes.AlreadyExists, "error: %v", err.Error())
+	}
+	if errors.Is(err, service.ErrRoleInUse) {
+		return status.Errorf(codes.FailedPrecondition, "error: %v", err.Error())
+	}
+	return status.Errorf(codes.Internal, "error: %v", err.Error())
+}
+
+func toRolePb(role model.Role) *pb.Role {
+	data := &pb.Role{
+		RoleId:        uint32(role.ID),
+		Name:          role.Name,
+		MfaRequired:   role.MFARequired,
+		RightsVersion: int32(role.RightsVersion),
+	}
+	for _, roleRight := range role.RoleRight {
+		data.RoleRights = append(data.RoleRights, toRoleRightPb(roleRight))
+	}
+	return data
+}
+
+func toRoleRightPb(roleRight model.RoleRight) *pb.RoleRight {
+	return &pb.RoleRight{
+		RoleRightId: uint32(roleRight.ID),
+		Section:     roleRight.Section,
+		Route:       roleRight.Route,
+		RCreate:     roleRight.RCreate == 1,
+		RRead:       roleRight.RRead == 1,
+		RUpdate:     roleRight.RUpdate == 1,
+		RDelete:     roleRight.RDelete == 1,
+	}
+}
+
+func boolToRight(allowed bool) int {
+	if allowed {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"tablelink_project/server/service"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+func TestRoleError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{gorm.ErrRecordNotFound, codes.NotFound},
+		{service.ErrRoleNameTaken, codes.AlreadyExists},
+		{service.ErrRoleInUse, codes.FailedPrecondition},
+		{fmt.Errorf("delete role: %w", service.ErrRoleInUse), codes.FailedPrecondition},
+		{errors.New("connection refused"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		if code := status.Code(roleError(tt.err)); code != tt.want {
+			t.Errorf("roleError(%v) code = %v, want %v", tt.err, code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	pb "tablelink_project/proto/api"
+	"tablelink_project/server/model"
+	"tablelink_project/server/service"
+	"tablelink_project/server/utils"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ServiceAccountController struct {
+	pb.UnimplementedServiceAccountServiceServer
+	serviceAccountService service.ServiceAccountService
+}
+
+func NewServiceAccountController(serviceAccountService service.ServiceAccountService) *ServiceAccountController {
+	return &ServiceAccountController{
+		serviceAccountService: serviceAccountService,
+	}
+}
+
+func (sc *ServiceAccountController) ListServiceAccounts(ctx context.Context, req *pb.ListServiceAccountsRequest) (*pb.ListServiceAccountsResponse, error) {
+	response := &pb.ListServiceAccountsResponse{}
+	accounts, err := sc.serviceAccountService.GetServiceAccounts()
+	if err != nil {
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, status.Errorf(codes.Internal, "error: %v", err.Error())
+	}
+
+	for _, account := range accounts {
+		response.Data = append(response.Data, toServiceAccountPb(account))
+	}
+	response.Status = true
+	response.Message = "success"
+	return response, nil
+}
+
+func (sc *ServiceAccountController) CreateServiceAccount(ctx context.Context, req *pb.CreateServiceAccountRequest) (*pb.CreateServiceAccountResponse, error) {
+	response := &pb.CreateServiceAccountResponse{}
+	actorID, ok := ctx.Value(utils.UserCtxKey).(uint)
+	if !ok {
+		response.Status = false
+		response.Message = "user not authenticated"
+		return response, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+
+	account, err := sc.serviceAccountService.CreateServiceAccount(ctx, actorID, model.User{
+		Name:   req.Name,
+		RoleID: uint(req.RoleId),
+	})
+	if err != nil {
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, serviceAccountError(err)
+	}
+
+	response.Status = true
+	response.Message = "success"
+	response.Data = toServiceAccountPb(*account)
+	return response, nil
+}
+
+func (sc *ServiceAccountController) ListAPIKeys(ctx context.Context, req *pb.ListAPIKeysRequest) (*pb.ListAPIKeysResponse, error) {
+	response := &pb.ListAPIKeysResponse{}
+	apiKeys, err := sc.serviceAccountService.GetAPIKeys(uint(req.ServiceAccountId))
+	if err != nil {
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, serviceAccountError(err)
+	}
+
+	for _, apiKey := range apiKeys {
+		response.Data = append(response.Data, toAPIKeyPb(apiKey))
+	}
+	response.Status = true
+	response.Message = "success"
+	return response, nil
+}
+
+func (sc *ServiceAccountController) CreateAPIKey(ctx context.Context, req *pb.CreateAPIKeyRequest) (*pb.CreateAPIKeyResponse, error) {
+	response := &pb.CreateAPIKeyResponse{}
+	apiKey, key, err := sc.serviceAccountService.CreateAPIKey(uint(req.ServiceAccountId), req.Name, req.Scopes)
+	if err != nil {
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, serviceAccountError(err)
+	}
+
+	response.Status = true
+	response.Message = "Store the API key now, it cannot be shown again"
+	response.Data = toAPIKeyPb(*apiKey)
+	response.ApiKey = key
+	return response, nil
+}
+
+func (sc *ServiceAccountController) RevokeAPIKey(ctx context.Context, req *pb.RevokeAPIKeyRequest) (*pb.RevokeAPIKeyResponse, error) {
+	response := &pb.RevokeAPIKeyResponse{}
+	err := sc.serviceAccountService.RevokeAPIKey(uint(req.ServiceAccountId), uint(req.KeyId))
+	if err != nil {
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, serviceAccountError(err)
+	}
+
+	response.Status = true
+	response.Message = "API key revoked"
+	return response, nil
+}
+
+func serviceAccountError(err error) error {
+	if validationErr := validationStatus(err); validationErr != nil {
+		return validationErr
+	}
+	if errors.Is(err, service.ErrServiceAccountNotFound) || errors.Is(err, service.ErrAPIKeyNotFound) {
+		return status.Errorf(codes.NotFound, "error: %v", err.Error())
+	}
+	if errors.Is(err, service.ErrRoleNotCovered) {
+		return status.Errorf(codes.PermissionDenied, "error: %v", err.Error())
+	}
+	return status.Errorf(codes.Internal, "error: %v", err.Error())
+}
+
+func toServiceAccountPb(account model.User) *pb.ServiceAccount {
+	return &pb.ServiceAccount{
+		ServiceAccountId: uint32(account.ID),
+		Name:             account.Name,
+		RoleId:           uint32(account.RoleID),
+		RoleName:         account.Role.Name,
+		CreatedAt:        account.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func toAPIKeyPb(apiKey model.APIKey) *pb.APIKey {
+	data := &pb.APIKey{
+		KeyId:     uint32(apiKey.ID),
+		Name:      apiKey.Name,
+		Prefix:    apiKey.Prefix,
+		Scopes:    strings.Fields(apiKey.Scopes),
+		CreatedAt: apiKey.CreatedAt.Format(time.RFC3339),
+	}
+	if apiKey.LastUsedAt != nil {
+		data.LastUsedAt = apiKey.LastUsedAt.Format(time.RFC3339)
+	}
+	return data
+}
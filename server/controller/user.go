@@ -2,67 +2,122 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"tablelink_project/config"
 	pb "tablelink_project/proto/api"
 	"tablelink_project/server/model"
+	"tablelink_project/server/repository"
 	"tablelink_project/server/service"
 	"tablelink_project/server/utils"
+	"time"
 
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
 )
 
 type UserController struct {
 	pb.UnimplementedUserServiceServer
-	userService service.UserService
+	userService   service.UserService
+	inviteService service.InviteService
 }
 
-func NewUserController(userService service.UserService) *UserController {
+func NewUserController(userService service.UserService, inviteService service.InviteService) *UserController {
 	return &UserController{
-		userService: userService,
+		userService:   userService,
+		inviteService: inviteService,
 	}
 }
 
 func (uc *UserController) GetAllUsers(ctx context.Context, req *pb.GetAllUsersRequest) (*pb.GetAllUsersResponse, error) {
-	err := uc.roleValidate(ctx)
 	response := &pb.GetAllUsersResponse{}
 
+	query := service.UserListQuery{
+		Filter: repository.UserFilter{
+			RoleID:       uint(req.RoleId),
+			EmailPrefix:  req.EmailPrefix,
+			NameContains: req.NameContains,
+		},
+		OrderBy:   req.OrderBy,
+		PageSize:  int(req.PageSize),
+		PageToken: req.PageToken,
+	}
+
+	var err error
+	query.Filter.LastAccessFrom, err = parseTimeFilter(req.LastAccessFrom)
 	if err != nil {
 		response.Status = false
 		response.Message = fmt.Sprintf("error: %v", err.Error())
-		return response, status.Errorf(codes.PermissionDenied, "access denied: %v", err.Error())
+		return response, status.Errorf(codes.InvalidArgument, "invalid last_access_from: %v", err.Error())
+	}
+	query.Filter.LastAccessTo, err = parseTimeFilter(req.LastAccessTo)
+	if err != nil {
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, status.Errorf(codes.InvalidArgument, "invalid last_access_to: %v", err.Error())
 	}
 
-	users, err := uc.userService.GetAllUsers()
+	users, err := uc.userService.GetAllUsers(query)
+	if errors.Is(err, service.ErrInvalidPageToken) || errors.Is(err, service.ErrInvalidOrderBy) {
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, status.Errorf(codes.InvalidArgument, "error: %v", err.Error())
+	}
 	if err != nil {
 		response.Status = false
 		response.Message = fmt.Sprintf("error: %v", err.Error())
 		return response, status.Errorf(codes.Internal, "error: %v", err.Error())
 	}
 
-	for _, user := range users {
-		response.Message = "success"
-		response.Status = true
-		response.Data = append(response.Data, &pb.User{
-			UserId:     uint32(user.ID),
-			Email:      user.Email,
-			RoleId:     uint32(user.Role.ID),
-			RoleName:   user.Role.Name,
-			LastAccess: user.LastAccess.Format("2006-01-02 15:04:05"),
-		})
+	response.Message = "success"
+	response.Status = true
+	response.NextPageToken = users.NextPageToken
+	response.TotalSize = users.TotalSize
+	for _, user := range users.Users {
+		response.Data = append(response.Data, toUserPb(user))
 	}
 	return response, nil
+}
+
+func (uc *UserController) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	response := &pb.GetUserResponse{}
 
+	user, err := uc.userService.GetUserByID(int(req.UserId))
+	if err != nil {
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, userError(err)
+	}
+
+	response.Status = true
+	response.Message = "success"
+	response.Data = toUserPb(*user)
+	return response, nil
 }
 
-func (uc *UserController) CreateUserRequest(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
-	err := uc.roleValidate(ctx)
+func (uc *UserController) GetMe(ctx context.Context, req *pb.GetMeRequest) (*pb.GetMeResponse, error) {
+	response := &pb.GetMeResponse{}
+	userID, ok := ctx.Value(utils.UserCtxKey).(uint)
+	if !ok {
+		response.Status = false
+		response.Message = "user not authenticated"
+		return response, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+
+	user, err := uc.userService.GetUserByID(int(userID))
 	if err != nil {
-		return nil, status.Errorf(codes.PermissionDenied, "access denied: %v", err.Error())
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, userError(err)
 	}
+
+	response.Status = true
+	response.Message = "success"
+	response.Data = toUserPb(*user)
+	return response, nil
+}
+
+func (uc *UserController) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
 	response := &pb.CreateUserResponse{}
 
 	user := model.User{
@@ -71,10 +126,13 @@ func (uc *UserController) CreateUserRequest(ctx context.Context, req *pb.CreateU
 		Password: req.Password,
 		RoleID:   uint(req.RoleId),
 	}
-	err = uc.userService.CreateUser(user)
+	err := uc.userService.CreateUser(user)
 	if err != nil {
 		response.Status = false
 		response.Message = fmt.Sprintf("error: %v", err.Error())
+		if validationErr := validationStatus(err); validationErr != nil {
+			return response, validationErr
+		}
 		return response, status.Errorf(codes.Internal, "error: %v", err.Error())
 	}
 
@@ -83,13 +141,79 @@ func (uc *UserController) CreateUserRequest(ctx context.Context, req *pb.CreateU
 	return response, nil
 }
 
-func (uc *UserController) UpdateUserRequest(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
-	err := uc.roleValidate(ctx)
+func (uc *UserController) InviteUser(ctx context.Context, req *pb.InviteUserRequest) (*pb.InviteUserResponse, error) {
+	response := &pb.InviteUserResponse{}
+	actorID, ok := ctx.Value(utils.UserCtxKey).(uint)
+	if !ok {
+		response.Status = false
+		response.Message = "user not authenticated"
+		return response, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+
+	invite := model.User{
+		Name:   req.Name,
+		Email:  req.Email,
+		RoleID: uint(req.RoleId),
+	}
+	user, expiredAt, err := uc.inviteService.InviteUser(ctx, actorID, invite)
 	if err != nil {
-		return nil, status.Errorf(codes.PermissionDenied, "access denied: %v", err.Error())
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		if validationErr := validationStatus(err); validationErr != nil {
+			return response, validationErr
+		}
+		return response, userError(err)
 	}
 
+	response.Status = true
+	response.Message = "success"
+	response.Data = toUserPb(*user)
+	response.ExpiredAt = expiredAt.Format(time.RFC3339)
+	return response, nil
+}
+
+func (uc *UserController) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
 	response := &pb.UpdateUserResponse{}
+	actorID, ok := ctx.Value(utils.UserCtxKey).(uint)
+	if !ok {
+		response.Status = false
+		response.Message = "user not authenticated"
+		return response, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+
+	userID := int(req.UserId)
+	update := model.User{
+		Name:   req.GetUser().GetName(),
+		Email:  req.GetUser().GetEmail(),
+		RoleID: uint(req.GetUser().GetRoleId()),
+	}
+	paths := req.GetUpdateMask().GetPaths()
+	// Requests of the deprecated PUT /users/user shape only carry a name
+	// and rename the caller.
+	if req.User == nil && req.UpdateMask == nil {
+		userID = int(actorID)
+		update = model.User{Name: req.Name}
+		paths = []string{"name"}
+	}
+
+	user, err := uc.userService.UpdateUser(ctx, actorID, userID, update, paths)
+	if err != nil {
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		if validationErr := validationStatus(err); validationErr != nil {
+			return response, validationErr
+		}
+		return response, userError(err)
+	}
+
+	response.Status = true
+	response.Message = "success"
+	response.Data = toUserPb(*user)
+	return response, nil
+}
+
+func (uc *UserController) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest) (*pb.ChangePasswordResponse, error) {
+	response := &pb.ChangePasswordResponse{}
 	userID, ok := ctx.Value(utils.UserCtxKey).(uint)
 	if !ok {
 		response.Status = false
@@ -97,38 +221,65 @@ func (uc *UserController) UpdateUserRequest(ctx context.Context, req *pb.UpdateU
 		return response, status.Errorf(codes.Unauthenticated, "user not authenticated")
 	}
 
-	user, err := uc.userService.GetUserByID(int(userID))
+	err := uc.userService.ChangePassword(ctx, userID, req.CurrentPassword, req.NewPassword)
 	if err != nil {
 		response.Status = false
 		response.Message = fmt.Sprintf("error: %v", err.Error())
-		return response, status.Errorf(codes.Internal, "error: %v", err.Error())
+		if validationErr := validationStatus(err); validationErr != nil {
+			return response, validationErr
+		}
+		return response, userError(err)
 	}
 
-	user.Name = req.Name
+	response.Status = true
+	response.Message = "success"
+	return response, nil
+}
 
-	err = uc.userService.UpdateUser(user)
+func (uc *UserController) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	response := &pb.ResetPasswordResponse{}
+	actorID, ok := ctx.Value(utils.UserCtxKey).(uint)
+	if !ok {
+		response.Status = false
+		response.Message = "user not authenticated"
+		return response, status.Errorf(codes.Unauthenticated, "user not authenticated")
+	}
+
+	expiredAt, err := uc.userService.IssuePasswordReset(ctx, actorID, uint(req.UserId))
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error: %v", err.Error())
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, userError(err)
 	}
 
-	return &pb.UpdateUserResponse{
-		Status:  true,
-		Message: "success",
-	}, nil
+	response.Status = true
+	response.Message = "success"
+	response.ExpiredAt = expiredAt.Format(time.RFC3339)
+	return response, nil
 }
 
-func (uc *UserController) DeleteUserRequest(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
-	err := uc.roleValidate(ctx)
+func (uc *UserController) UnlockUser(ctx context.Context, req *pb.UnlockUserRequest) (*pb.UnlockUserResponse, error) {
+	response := &pb.UnlockUserResponse{}
+
+	err := uc.userService.UnlockUser(ctx, uint(req.UserId))
 	if err != nil {
-		return nil, status.Errorf(codes.PermissionDenied, "access denied: %v", err.Error())
+		response.Status = false
+		response.Message = fmt.Sprintf("error: %v", err.Error())
+		return response, userError(err)
 	}
 
+	response.Status = true
+	response.Message = "success"
+	return response, nil
+}
+
+func (uc *UserController) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
 	response := &pb.DeleteUserResponse{}
-	err = uc.userService.DeleteUser(int(req.UserId))
+	err := uc.userService.DeleteUser(ctx, int(req.UserId))
 	if err != nil {
 		response.Status = false
 		response.Message = fmt.Sprintf("error: %v", err.Error())
-		return response, status.Errorf(codes.Internal, "error: %v", err.Error())
+		return response, userError(err)
 	}
 	return &pb.DeleteUserResponse{
 		Status:  true,
@@ -136,34 +287,42 @@ func (uc *UserController) DeleteUserRequest(ctx context.Context, req *pb.DeleteU
 	}, nil
 }
 
-func (uc *UserController) roleValidate(ctx context.Context) error {
-	userID, ok := ctx.Value(utils.UserCtxKey).(uint)
-	if !ok {
-		return status.Errorf(codes.Unauthenticated, "user not authenticated")
+func userError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status.Errorf(codes.NotFound, "user not found")
 	}
-
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return status.Errorf(codes.Unauthenticated, "missing metadata")
+	if errors.Is(err, service.ErrRoleNotCovered) || errors.Is(err, service.ErrOwnRoleChange) {
+		return status.Errorf(codes.PermissionDenied, "error: %v", err.Error())
 	}
+	return status.Errorf(codes.Internal, "error: %v", err.Error())
+}
 
-	sections := md.Get("X-Link-Service")
-	if len(sections) == 0 {
-		return status.Errorf(codes.Unauthenticated, "missing section in metadata")
+func toUserPb(user model.User) *pb.User {
+	data := &pb.User{
+		UserId:     uint32(user.ID),
+		Email:      user.Email,
+		Name:       user.Name,
+		RoleId:     uint32(user.Role.ID),
+		RoleName:   user.Role.Name,
+		LastAccess: user.LastAccess.Format("2006-01-02 15:04:05"),
+		Status:     user.Status,
+		Kind:       user.Kind,
 	}
-	section := sections[0]
-
-	route, _ := grpc.Method(ctx)
+	for _, roleRight := range user.Role.RoleRight {
+		data.RoleRights = append(data.RoleRights, toRoleRightPb(roleRight))
+	}
+	return data
+}
 
-	restMapping, exists := config.GrpcToRestfulMapping[route]
-	if !exists {
-		return status.Errorf(codes.InvalidArgument, "invalid route: %s", route)
+func parseTimeFilter(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
 	}
 
-	err := uc.userService.ValidateRoleRights(userID, section, restMapping.Route, restMapping.Method)
+	parsed, err := time.Parse(time.RFC3339, value)
 	if err != nil {
-		return status.Errorf(codes.PermissionDenied, "access denied: %v", err.Error())
+		return nil, err
 	}
 
-	return nil
+	return &parsed, nil
 }
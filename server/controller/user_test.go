@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	pb "tablelink_project/proto/api"
+	"tablelink_project/server/model"
+	"tablelink_project/server/service"
+	"tablelink_project/server/utils"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"gorm.io/gorm"
+)
+
+// stubUserService serves a fixed set of users and records the last update.
+// Any other UserService method panics through the nil embedded interface.
+type stubUserService struct {
+	service.UserService
+	users   map[int]*model.User
+	updated *userUpdate
+}
+
+type userUpdate struct {
+	actorID uint
+	userID  int
+	name    string
+	paths   []string
+}
+
+func (s *stubUserService) GetUserByID(userID int) (*model.User, error) {
+	user, ok := s.users[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func (s *stubUserService) UpdateUser(ctx context.Context, actorID uint, userID int, update model.User, paths []string) (*model.User, error) {
+	s.updated = &userUpdate{actorID: actorID, userID: userID, name: update.Name, paths: paths}
+	return s.GetUserByID(userID)
+}
+
+func (s *stubUserService) DeleteUser(ctx context.Context, userID int) error {
+	if _, ok := s.users[userID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(s.users, userID)
+	return nil
+}
+
+func newTestUserController() (*UserController, *stubUserService) {
+	users := &stubUserService{users: map[int]*model.User{
+		7: {
+			ID:    7,
+			Email: "jane@example.com",
+			Name:  "Jane",
+			Role: model.Role{
+				ID:        2,
+				Name:      "editor",
+				RoleRight: []model.RoleRight{{ID: 3, Section: "users", Route: "/users", RRead: 1}},
+			},
+		},
+		9: {ID: 9, Email: "john@example.com", Name: "John"},
+	}}
+	return NewUserController(users, nil), users
+}
+
+func TestUserControllerGetUser(t *testing.T) {
+	controller, _ := newTestUserController()
+
+	response, err := controller.GetUser(context.Background(), &pb.GetUserRequest{UserId: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := response.Data
+	if data.UserId != 7 || data.Email != "jane@example.com" || data.RoleName != "editor" {
+		t.Errorf("GetUser() = %+v, want user 7", data)
+	}
+	if len(data.RoleRights) != 1 || !data.RoleRights[0].RRead || data.RoleRights[0].RCreate {
+		t.Errorf("GetUser() role rights = %+v, want read on /users", data.RoleRights)
+	}
+
+	_, err = controller.GetUser(context.Background(), &pb.GetUserRequest{UserId: 8})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Errorf("GetUser(unknown) code = %v, want %v", code, codes.NotFound)
+	}
+}
+
+func TestUserControllerGetMe(t *testing.T) {
+	controller, _ := newTestUserController()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantCode codes.Code
+	}{
+		{"caller", context.WithValue(context.Background(), utils.UserCtxKey, uint(7)), codes.OK},
+		{"deleted caller", context.WithValue(context.Background(), utils.UserCtxKey, uint(8)), codes.NotFound},
+		{"unauthenticated", context.Background(), codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := controller.GetMe(tt.ctx, &pb.GetMeRequest{})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("GetMe() code = %v, want %v", code, tt.wantCode)
+			}
+			if err == nil && response.Data.UserId != 7 {
+				t.Errorf("GetMe() = %+v, want user 7", response.Data)
+			}
+		})
+	}
+}
+
+func TestUserControllerDeleteUser(t *testing.T) {
+	controller, _ := newTestUserController()
+
+	if _, err := controller.DeleteUser(context.Background(), &pb.DeleteUserRequest{UserId: 7}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := controller.DeleteUser(context.Background(), &pb.DeleteUserRequest{UserId: 7})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Errorf("DeleteUser(deleted) code = %v, want %v", code, codes.NotFound)
+	}
+}
+
+func TestUserControllerUpdateUser(t *testing.T) {
+	ctx := context.WithValue(context.Background(), utils.UserCtxKey, uint(7))
+
+	tests := []struct {
+		name string
+		req  *pb.UpdateUserRequest
+		want userUpdate
+	}{
+		{
+			"field mask",
+			&pb.UpdateUserRequest{UserId: 9, User: &pb.User{Name: "Johnny"}, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}}},
+			userUpdate{actorID: 7, userID: 9, name: "Johnny", paths: []string{"name"}},
+		},
+		{
+			"deprecated self-service rename",
+			&pb.UpdateUserRequest{UserId: 9, Name: "Janet"},
+			userUpdate{actorID: 7, userID: 7, name: "Janet", paths: []string{"name"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller, users := newTestUserController()
+
+			if _, err := controller.UpdateUser(ctx, tt.req); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*users.updated, tt.want) {
+				t.Errorf("UpdateUser() updated %+v, want %+v", *users.updated, tt.want)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"strings"
 
 	"tablelink_project/config"
 	"tablelink_project/proto/api"
@@ -14,6 +15,7 @@ import (
 	mid "tablelink_project/server/middleware"
 	"tablelink_project/server/repository"
 	"tablelink_project/server/service"
+	"tablelink_project/server/utils"
 
 	"github.com/go-redis/redis/v8"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
@@ -21,6 +23,8 @@ import (
 	"github.com/joho/godotenv"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
@@ -46,39 +50,134 @@ func main() {
 	log.Println("Connected to Redis")
 
 	userRepo := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepo)
-	authController := controller.NewAuthController(userService, redisClient)
-	userController := controller.NewUserController(userService)
+	roleRepo := repository.NewRoleRepository(db)
+	tokenRepo := repository.NewTokenRepository(db)
+	tokenDenylistRepo := repository.NewTokenDenylistRepository(redisClient)
+	sessionRepo := repository.NewSessionRepository(redisClient)
+	keyring := config.BuildKeyring()
+	config.WatchKeyring(keyring)
+	tokenGenerator := utils.NewJWT(config.BuildJWTConfig(keyring))
+	permissionCacheRepo := repository.NewPermissionCacheRepository(redisClient)
+	permissionService := service.NewPermissionService(roleRepo, permissionCacheRepo)
+	tokenService := service.NewTokenService(tokenRepo, tokenDenylistRepo, sessionRepo, userRepo, permissionService, tokenGenerator, config.EmbedRoleClaims())
+	passwordResetRepo := repository.NewPasswordResetRepository(redisClient)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(redisClient)
+	mfaRepo := repository.NewMFARepository(db)
+	mfaService := service.NewMFAService(userRepo, roleRepo, mfaRepo, utils.NewTOTP(utils.SystemClock{}), os.Getenv("MFA_TOTP_ISSUER"))
+	loginThrottle := service.NewLoginThrottle(loginAttemptRepo, config.BuildLoginThrottleConfig())
+	passwordPolicy := config.BuildPasswordPolicy()
+	mailer := config.BuildMailer()
+	userService := service.NewUserService(userRepo, roleRepo, passwordResetRepo, tokenService, permissionService, passwordPolicy, loginThrottle, mfaService, mailer, os.Getenv("PASSWORD_RESET_URL"))
+	inviteService := service.NewInviteService(userRepo, roleRepo, permissionService, tokenGenerator, mailer, passwordPolicy, os.Getenv("INVITE_URL"))
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	serviceAccountService := service.NewServiceAccountService(userRepo, roleRepo, apiKeyRepo, permissionService)
+	introspectionService := service.NewIntrospectionService(tokenGenerator, tokenService, permissionService, serviceAccountService, userRepo)
+	authController := controller.NewAuthController(userService, tokenService, mfaService, inviteService, introspectionService, tokenGenerator)
+	userController := controller.NewUserController(userService, inviteService)
+	roleService := service.NewRoleService(roleRepo, userRepo, permissionService)
+	roleController := controller.NewRoleController(roleService)
+	serviceAccountController := controller.NewServiceAccountController(serviceAccountService)
 
 	grpcServer := grpc.NewServer(
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
-			mid.JwtAuthInterceptor(),
+			mid.ClientIPInterceptor(config.BuildTrustedProxies()),
+			mid.JwtAuthInterceptor(tokenGenerator, tokenService, serviceAccountService),
+			mid.ImpersonationAuditInterceptor(),
+			mid.AuthorizationInterceptor(userService),
 		)),
 	)
 	api.RegisterAuthServiceServer(grpcServer, authController)
 	api.RegisterUserServiceServer(grpcServer, userController)
+	api.RegisterRoleServiceServer(grpcServer, roleController)
+	api.RegisterServiceAccountServiceServer(grpcServer, serviceAccountController)
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
 
 	ctx := context.Background()
-	mux := runtime.NewServeMux()
+	permissionService.WatchInvalidations(ctx)
+
+	// The gateway dials the gRPC server on the same address it listens on.
+	grpcAddress := fmt.Sprintf(":%s", os.Getenv("GRPC_PORT"))
+
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+	)
 	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
-	err = api.RegisterAuthServiceHandlerFromEndpoint(ctx, mux, ":50051", opts)
+	authConn, err := grpc.NewClient(grpcAddress, opts...)
+	if err != nil {
+		log.Fatalf("failed to create AuthService client: %v", err)
+	}
+	defer authConn.Close()
+	authClient := api.NewAuthServiceClient(authConn)
+
+	err = api.RegisterAuthServiceHandlerClient(ctx, mux, authClient)
 	if err != nil {
 		log.Fatalf("failed to register AuthService handler: %v", err)
 	}
 
-	err = api.RegisterUserServiceHandlerFromEndpoint(ctx, mux, ":50051", opts)
+	err = api.RegisterUserServiceHandlerFromEndpoint(ctx, mux, grpcAddress, opts)
 	if err != nil {
 		log.Fatalf("failed to register UserService handler: %v", err)
 	}
 
+	err = api.RegisterRoleServiceHandlerFromEndpoint(ctx, mux, grpcAddress, opts)
+	if err != nil {
+		log.Fatalf("failed to register RoleService handler: %v", err)
+	}
+
+	err = api.RegisterServiceAccountServiceHandlerFromEndpoint(ctx, mux, grpcAddress, opts)
+	if err != nil {
+		log.Fatalf("failed to register ServiceAccountService handler: %v", err)
+	}
+
+	err = mux.HandlePath(http.MethodGet, "/.well-known/jwks.json", controller.NewJWKSHandler(tokenGenerator))
+	if err != nil {
+		log.Fatalf("failed to register JWKS handler: %v", err)
+	}
+
+	err = mux.HandlePath(http.MethodPost, "/oauth/introspect", controller.NewIntrospectionHandler(mux, authClient))
+	if err != nil {
+		log.Fatalf("failed to register introspection handler: %v", err)
+	}
+
+	if oidcConfig, provisioning, ok := config.BuildOIDCConfig(); ok {
+		oidcStateRepo := repository.NewOIDCStateRepository(redisClient)
+		oidcService := service.NewOIDCService(utils.NewOIDCProvider(oidcConfig), oidcStateRepo, userRepo, userService, provisioning)
+
+		err = mux.HandlePath(http.MethodGet, "/auth/oidc/start", controller.NewOIDCStartHandler(mux, oidcService))
+		if err != nil {
+			log.Fatalf("failed to register OIDC start handler: %v", err)
+		}
+
+		err = mux.HandlePath(http.MethodGet, "/auth/oidc/callback", controller.NewOIDCCallbackHandler(mux, oidcService))
+		if err != nil {
+			log.Fatalf("failed to register OIDC callback handler: %v", err)
+		}
+	}
+
 	httpServer := &http.Server{
 		Addr:    fmt.Sprintf(":%s", os.Getenv("PORT")),
 		Handler: mux,
 	}
 
-	// Start both servers
+	// The metrics listener stays off the gateway and, by default, on loopback.
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("GET /debug/vars", controller.NewMetricsHandler("permission_cache"))
+	metricsServer := &http.Server{
+		Addr:    config.MetricsAddress(),
+		Handler: metricsMux,
+	}
+
+	// Start the servers
 	go func() {
-		lis, err := net.Listen("tcp", fmt.Sprintf(":%s", os.Getenv("GRPC_PORT")))
+		log.Println("metrics server is running at", metricsServer.Addr)
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("failed to serve metrics: %v", err)
+		}
+	}()
+
+	go func() {
+		lis, err := net.Listen("tcp", grpcAddress)
 		if err != nil {
 			log.Fatalf("failed to listen: %v", err)
 		}
@@ -93,3 +192,21 @@ func main() {
 		log.Fatalf("failed to serve HTTP: %v", err)
 	}
 }
+
+// incomingHeaderMatcher forwards the X-API-Key header as x-api-key
+// metadata and keeps the gateway's default for every other header.
+func incomingHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, "X-API-Key") {
+		return "x-api-key", true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeaderMatcher forwards retry-after as the standard HTTP header and
+// keeps the gateway's default prefix for every other response metadata.
+func outgoingHeaderMatcher(key string) (string, bool) {
+	if key == "retry-after" {
+		return "Retry-After", true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
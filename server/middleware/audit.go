@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"tablelink_project/server/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// ImpersonationAuditInterceptor logs every call made with an impersonation
+// token together with the real actor and the outcome. It must run after
+// JwtAuthInterceptor and before AuthorizationInterceptor, so calls denied
+// during impersonation are logged as well.
+func ImpersonationAuditInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		actor, ok := ctx.Value(utils.ActorCtxKey).(*utils.ActorClaim)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		resp, err := handler(ctx, req)
+		userID, _ := ctx.Value(utils.UserCtxKey).(uint)
+		log.Printf("audit: user %s impersonating user %d called %s: %s", actor.Subject, userID, info.FullMethod, status.Code(err))
+		return resp, err
+	}
+}
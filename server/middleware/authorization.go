@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"tablelink_project/server/service"
+	"tablelink_project/server/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthorizationInterceptor checks the caller's role rights for every method
+// that is not public. It must run after JwtAuthInterceptor, which puts the
+// caller's user ID in the context. Access tokens that embed the caller's role
+// are checked against the cached rights of that role. Methods without a
+// (tablelink.permission) option are denied. Impersonation tokens
+// are read-only: they may only call methods that need a READ right, or
+// authenticated-only methods that are not sensitive.
+func AuthorizationInterceptor(userService service.UserService) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		userID, ok := ctx.Value(utils.UserCtxKey).(uint)
+		if !ok {
+			return nil, status.Errorf(codes.Unauthenticated, "user not authenticated")
+		}
+
+		permission, exists := utils.LookupPermission(info.FullMethod)
+		if !exists {
+			return nil, status.Errorf(codes.PermissionDenied, "access denied: no permission rule for %s", info.FullMethod)
+		}
+
+		if _, impersonating := ctx.Value(utils.ActorCtxKey).(*utils.ActorClaim); impersonating && !allowedWhileImpersonating(permission) {
+			return nil, status.Errorf(codes.PermissionDenied, "access denied: %s is not allowed while impersonating", info.FullMethod)
+		}
+
+		if permission.AuthenticatedOnly {
+			return handler(ctx, req)
+		}
+
+		section := permission.Section
+		if section == "" {
+			md, _ := metadata.FromIncomingContext(ctx)
+			sections := md.Get("X-Link-Service")
+			if len(sections) == 0 {
+				return nil, status.Errorf(codes.Unauthenticated, "missing section in metadata")
+			}
+			section = sections[0]
+		}
+
+		var err error
+		if role, ok := ctx.Value(utils.RoleCtxKey).(*utils.RoleClaims); ok {
+			err = userService.ValidateTokenRights(ctx, role, section, permission.Route, permission.Method)
+		} else {
+			err = userService.ValidateRoleRights(ctx, userID, section, permission.Route, permission.Method)
+		}
+		if errors.Is(err, service.ErrStaleToken) {
+			return nil, status.Errorf(codes.Unauthenticated, "%v, refresh it", err)
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.PermissionDenied, "access denied: %v", err.Error())
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func allowedWhileImpersonating(permission *utils.MethodPermission) bool {
+	if permission.Sensitive {
+		return false
+	}
+	return permission.AuthenticatedOnly || permission.Method == http.MethodGet
+}
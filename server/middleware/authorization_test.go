@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"tablelink_project/proto/api"
+	"tablelink_project/server/service"
+	"tablelink_project/server/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// stubUserService allows a fixed set of section/route/method rights, and
+// treats token roles with a rights version other than version as stale. Any
+// other UserService method panics through the nil embedded interface.
+type stubUserService struct {
+	service.UserService
+	allowed map[string]bool
+	version int
+}
+
+func (s *stubUserService) ValidateRoleRights(ctx context.Context, userID uint, section, route, method string) error {
+	if !s.allowed[section+" "+route+" "+method] {
+		return errors.New("role has no access to " + route)
+	}
+	return nil
+}
+
+func (s *stubUserService) ValidateTokenRights(ctx context.Context, role *utils.RoleClaims, section, route, method string) error {
+	if role.RightsVersion != s.version {
+		return service.ErrStaleToken
+	}
+	return s.ValidateRoleRights(ctx, 0, section, route, method)
+}
+
+func TestAuthorizationInterceptor(t *testing.T) {
+	userService := &stubUserService{allowed: map[string]bool{
+		"users /users " + http.MethodGet:       true,
+		"users /users/user " + http.MethodPost: true,
+	}, version: 3}
+	interceptor := AuthorizationInterceptor(userService)
+
+	authenticated := context.WithValue(context.Background(), utils.UserCtxKey, uint(7))
+	withSection := func(ctx context.Context, section string) context.Context {
+		return metadata.NewIncomingContext(ctx, metadata.Pairs("X-Link-Service", section))
+	}
+	impersonating := context.WithValue(authenticated, utils.ActorCtxKey, &utils.ActorClaim{Subject: "1"})
+	withRole := func(version int) context.Context {
+		return context.WithValue(authenticated, utils.RoleCtxKey, &utils.RoleClaims{RoleID: 2, RightsVersion: version})
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		wantCode codes.Code
+	}{
+		{"public method", context.Background(), api.AuthService_Login_FullMethodName, codes.OK},
+		{"unauthenticated", withSection(context.Background(), "users"), api.UserService_GetAllUsers_FullMethodName, codes.Unauthenticated},
+		{"method without permission rule", withSection(authenticated, "users"), "/user.UserService/Missing", codes.PermissionDenied},
+		{"missing section", authenticated, api.UserService_GetAllUsers_FullMethodName, codes.Unauthenticated},
+		{"right granted", withSection(authenticated, "users"), api.UserService_GetAllUsers_FullMethodName, codes.OK},
+		{"other section", withSection(authenticated, "roles"), api.UserService_GetAllUsers_FullMethodName, codes.PermissionDenied},
+		{"right missing", withSection(authenticated, "users"), api.UserService_DeleteUser_FullMethodName, codes.PermissionDenied},
+		{"token role granted", withSection(withRole(3), "users"), api.UserService_GetAllUsers_FullMethodName, codes.OK},
+		{"token role missing right", withSection(withRole(3), "users"), api.UserService_DeleteUser_FullMethodName, codes.PermissionDenied},
+		{"stale token role", withSection(withRole(2), "users"), api.UserService_GetAllUsers_FullMethodName, codes.Unauthenticated},
+		{"authenticated only", authenticated, api.UserService_GetMe_FullMethodName, codes.OK},
+		{"create granted", withSection(authenticated, "users"), api.UserService_CreateUser_FullMethodName, codes.OK},
+		{"impersonated read", withSection(impersonating, "users"), api.UserService_GetAllUsers_FullMethodName, codes.OK},
+		{"impersonated create", withSection(impersonating, "users"), api.UserService_CreateUser_FullMethodName, codes.PermissionDenied},
+		{"impersonated authenticated only", impersonating, api.UserService_GetMe_FullMethodName, codes.OK},
+		{"impersonated sensitive", impersonating, api.UserService_ChangePassword_FullMethodName, codes.PermissionDenied},
+		{"authenticated only without user", context.Background(), api.UserService_GetMe_FullMethodName, codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return nil, nil
+			}
+
+			_, err := interceptor(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("interceptor() code = %v, want %v (%v)", code, tt.wantCode, err)
+			}
+			if called != (tt.wantCode == codes.OK) {
+				t.Errorf("handler called = %v, want %v", called, tt.wantCode == codes.OK)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"tablelink_project/server/utils"
+
+	"google.golang.org/grpc"
+)
+
+// ClientIPInterceptor resolves the caller's IP address once per call and
+// puts it in the context for utils.ClientIP. X-Forwarded-For is only
+// honoured on calls from trustedProxies. It must run first.
+func ClientIPInterceptor(trustedProxies []*net.IPNet) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		ctx = context.WithValue(ctx, utils.ClientIPCtxKey, utils.ResolveClientIP(ctx, trustedProxies))
+		return handler(ctx, req)
+	}
+}
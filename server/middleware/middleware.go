@@ -2,41 +2,165 @@ package middleware
 
 import (
 	"context"
-	"net/http"
+	"errors"
+	"strings"
+	"tablelink_project/server/service"
 	"tablelink_project/server/utils"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
-func JwtAuthInterceptor() grpc.UnaryServerInterceptor {
+// publicMethods can be called without an access token in the metadata and
+// skip authorization. Logout carries the token to revoke in its request body
+// instead.
+var publicMethods = map[string]bool{
+	"/auth.AuthService/Login":                 true,
+	"/auth.AuthService/Logout":                true,
+	"/auth.AuthService/RefreshToken":          true,
+	"/auth.AuthService/CompletePasswordReset": true,
+	"/auth.AuthService/AcceptInvite":          true,
+	"/grpc.health.v1.Health/Check":            true,
+}
+
+// mfaChallengeMethods also accept an MFA challenge token in place of an
+// access token, so users can enrol and verify their second factor before
+// they get credentials.
+var mfaChallengeMethods = map[string]bool{
+	"/auth.AuthService/EnrollTOTP": true,
+	"/auth.AuthService/VerifyTOTP": true,
+}
+
+// apiKeyAuthScheme is the authorization scheme an API key can be sent with
+// instead of the x-api-key metadata.
+const apiKeyAuthScheme = "ApiKey "
+
+// JwtAuthInterceptor authenticates the caller with the access token in the
+// authorization metadata, or with a service account API key sent as
+// x-api-key or as an ApiKey authorization.
+func JwtAuthInterceptor(tokenGenerator utils.CredentialTokenGenerator, tokenService service.TokenService, serviceAccountService service.ServiceAccountService) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req interface{},
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
 		md, ok := metadata.FromIncomingContext(ctx)
 		if !ok {
-			return nil, status.Errorf(http.StatusUnauthorized, "missing metadata")
+			return nil, status.Errorf(codes.Unauthenticated, "missing metadata")
+		}
+
+		if apiKey, ok := apiKeyFromMetadata(md); ok {
+			return handleAPIKey(ctx, req, info, handler, serviceAccountService, apiKey)
 		}
 
 		tokens := md.Get("authorization")
 		if len(tokens) == 0 {
-			return nil, status.Errorf(http.StatusUnauthorized, "missing authorization token")
+			return nil, status.Errorf(codes.Unauthenticated, "missing authorization token")
+		}
+		claim, err := tokenGenerator.ParseToken(tokens[0])
+		if err != nil && mfaChallengeMethods[info.FullMethod] {
+			challenge, challengeErr := tokenGenerator.ParseMFAChallenge(tokens[0])
+			if challengeErr == nil {
+				return handleMFAChallenge(ctx, req, handler, tokenService, challenge)
+			}
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
 		}
-		token := tokens[0]
 
-		if info.FullMethod != "/auth/login" {
-			userID, err := utils.ValidateToken(token)
+		revoked, err := tokenService.IsTokenRevoked(ctx, claim.Id)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to check token revocation: %v", err)
+		}
+		if revoked {
+			return nil, status.Errorf(codes.Unauthenticated, "token has been revoked")
+		}
+
+		if claim.SessionID != "" {
+			err = tokenService.TouchSession(ctx, claim.SessionID, utils.ClientIP(ctx))
+			if errors.Is(err, service.ErrSessionNotFound) {
+				return nil, status.Errorf(codes.Unauthenticated, "session has been revoked")
+			}
 			if err != nil {
-				return nil, status.Errorf(http.StatusUnauthorized, "invalid token: %v", err)
+				return nil, status.Errorf(codes.Internal, "failed to check session: %v", err)
 			}
+			ctx = context.WithValue(ctx, utils.SessionCtxKey, claim.SessionID)
+		}
 
-			ctx = context.WithValue(ctx, utils.UserCtxKey, userID)
+		if claim.RoleClaims != nil {
+			ctx = context.WithValue(ctx, utils.RoleCtxKey, claim.RoleClaims)
+		}
+		if claim.Actor != nil {
+			ctx = context.WithValue(ctx, utils.ActorCtxKey, claim.Actor)
 		}
+		ctx = context.WithValue(ctx, utils.UserCtxKey, claim.UserID)
 
 		return handler(ctx, req)
 	}
 }
+
+func handleMFAChallenge(
+	ctx context.Context,
+	req interface{},
+	handler grpc.UnaryHandler,
+	tokenService service.TokenService,
+	challenge *utils.MFAChallengeClaim,
+) (interface{}, error) {
+	used, err := tokenService.IsTokenRevoked(ctx, challenge.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to check token revocation: %v", err)
+	}
+	if used {
+		return nil, status.Errorf(codes.Unauthenticated, "mfa challenge has already been used")
+	}
+
+	ctx = context.WithValue(ctx, utils.MFAChallengeCtxKey, challenge)
+	ctx = context.WithValue(ctx, utils.UserCtxKey, challenge.UserID)
+
+	return handler(ctx, req)
+}
+
+func apiKeyFromMetadata(md metadata.MD) (string, bool) {
+	if keys := md.Get("x-api-key"); len(keys) > 0 {
+		return keys[0], true
+	}
+	if tokens := md.Get("authorization"); len(tokens) > 0 && strings.HasPrefix(tokens[0], apiKeyAuthScheme) {
+		return strings.TrimPrefix(tokens[0], apiKeyAuthScheme), true
+	}
+	return "", false
+}
+
+// handleAPIKey authenticates the call as the key's service account, so the
+// account's role rights apply as for any user, and rejects methods outside
+// the key's scopes.
+func handleAPIKey(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+	serviceAccountService service.ServiceAccountService,
+	apiKey string,
+) (interface{}, error) {
+	identity, err := serviceAccountService.Authenticate(apiKey)
+	if errors.Is(err, service.ErrInvalidAPIKey) {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid api key")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to check api key: %v", err)
+	}
+
+	if !identity.Allows(info.FullMethod) {
+		return nil, status.Errorf(codes.PermissionDenied, "api key %s is not scoped for %s", identity.Prefix, info.FullMethod)
+	}
+
+	ctx = context.WithValue(ctx, utils.UserCtxKey, identity.ServiceAccountID)
+
+	return handler(ctx, req)
+}
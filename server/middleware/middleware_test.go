@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"tablelink_project/server/service"
+	"tablelink_project/server/utils"
+
+	"github.com/golang-jwt/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// stubTokenService answers revocation checks from a fixed set of token
+// ids and live sessions. Any other TokenService method panics through the
+// nil embedded interface.
+type stubTokenService struct {
+	service.TokenService
+	revoked  map[string]bool
+	sessions map[string]bool
+}
+
+func (s *stubTokenService) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return s.revoked[tokenID], nil
+}
+
+func (s *stubTokenService) TouchSession(ctx context.Context, sessionID, ip string) error {
+	if !s.sessions[sessionID] {
+		return service.ErrSessionNotFound
+	}
+	return nil
+}
+
+// stubServiceAccounts authenticates a fixed set of API keys. Any other
+// ServiceAccountService method panics through the nil embedded interface.
+type stubServiceAccounts struct {
+	service.ServiceAccountService
+	keys map[string]*service.APIKeyIdentity
+}
+
+func (s *stubServiceAccounts) Authenticate(apiKey string) (*service.APIKeyIdentity, error) {
+	identity, ok := s.keys[apiKey]
+	if !ok {
+		return nil, service.ErrInvalidAPIKey
+	}
+	return identity, nil
+}
+
+func testJWT(t *testing.T, secret string) *utils.JWT {
+	t.Helper()
+
+	key, err := utils.NewHMACKey("test", jwt.SigningMethodHS256, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring, err := utils.NewKeyring([]*utils.SigningKey{key}, key.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return utils.NewJWT(utils.JWTConfig{Keyring: ring})
+}
+
+func TestJwtAuthInterceptor(t *testing.T) {
+	tokenGenerator := testJWT(t, "test-secret")
+	valid, err := tokenGenerator.Generate(7, nil, "session", "family")
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked, err := tokenGenerator.Generate(7, nil, "session", "family")
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreign, err := testJWT(t, "other-secret").Generate(7, nil, "session", "family")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ended, err := tokenGenerator.Generate(7, nil, "ended-session", "other-family")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		method    string
+		metadata  metadata.MD
+		code      codes.Code
+		userID    uint
+		sessionID string
+	}{
+		{"public method without token", "/auth.AuthService/Login", nil, codes.OK, 0, ""},
+		{"missing metadata", "/user.UserService/GetAllUsers", nil, codes.Unauthenticated, 0, ""},
+		{"missing token", "/user.UserService/GetAllUsers", metadata.Pairs(), codes.Unauthenticated, 0, ""},
+		{"malformed token", "/user.UserService/GetAllUsers", metadata.Pairs("authorization", "Bearer nonsense"), codes.Unauthenticated, 0, ""},
+		{"foreign token", "/user.UserService/GetAllUsers", metadata.Pairs("authorization", foreign.AccessToken), codes.Unauthenticated, 0, ""},
+		{"refresh token", "/user.UserService/GetAllUsers", metadata.Pairs("authorization", valid.RefreshToken), codes.Unauthenticated, 0, ""},
+		{"revoked token", "/user.UserService/GetAllUsers", metadata.Pairs("authorization", "Bearer "+revoked.AccessToken), codes.Unauthenticated, 0, ""},
+		{"valid token", "/user.UserService/GetAllUsers", metadata.Pairs("authorization", "Bearer "+valid.AccessToken), codes.OK, 7, "session"},
+		{"api key", "/user.UserService/GetAllUsers", metadata.Pairs("x-api-key", "tlk_key"), codes.OK, 9, ""},
+		{"api key authorization", "/user.UserService/GetAllUsers", metadata.Pairs("authorization", "ApiKey tlk_key"), codes.OK, 9, ""},
+		{"api key out of scope", "/user.UserService/DeleteUser", metadata.Pairs("x-api-key", "tlk_key"), codes.PermissionDenied, 0, ""},
+		{"unknown api key", "/user.UserService/GetAllUsers", metadata.Pairs("x-api-key", "tlk_other"), codes.Unauthenticated, 0, ""},
+		{"token of a revoked session", "/user.UserService/GetAllUsers", metadata.Pairs("authorization", "Bearer "+ended.AccessToken), codes.Unauthenticated, 0, ""},
+	}
+
+	interceptor := JwtAuthInterceptor(tokenGenerator, &stubTokenService{
+		revoked:  map[string]bool{revoked.AccessTokenID: true},
+		sessions: map[string]bool{"session": true},
+	}, &stubServiceAccounts{keys: map[string]*service.APIKeyIdentity{
+		"tlk_key": {ServiceAccountID: 9, Prefix: "tlk_key", Scopes: []string{"user.UserService/GetAllUsers"}},
+	}})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.metadata != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.metadata)
+			}
+
+			var userID uint
+			var sessionID string
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				userID, _ = ctx.Value(utils.UserCtxKey).(uint)
+				sessionID, _ = ctx.Value(utils.SessionCtxKey).(string)
+				return "ok", nil
+			}
+
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("interceptor() code = %s, want %s (%v)", code, tt.code, err)
+			}
+			if userID != tt.userID {
+				t.Errorf("user id in context = %d, want %d", userID, tt.userID)
+			}
+			if sessionID != tt.sessionID {
+				t.Errorf("session id in context = %q, want %q", sessionID, tt.sessionID)
+			}
+		})
+	}
+}
+
+func TestJwtAuthInterceptorRoleClaims(t *testing.T) {
+	tokenGenerator := testJWT(t, "test-secret")
+	role := &utils.RoleClaims{RoleID: 2, RoleName: "editor", RightsVersion: 3, Permissions: "digest"}
+	interceptor := JwtAuthInterceptor(tokenGenerator, &stubTokenService{
+		sessions: map[string]bool{"session": true},
+	}, &stubServiceAccounts{})
+
+	for _, want := range []*utils.RoleClaims{nil, role} {
+		pair, err := tokenGenerator.Generate(7, want, "session", "family")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+pair.AccessToken))
+
+		var got *utils.RoleClaims
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			got, _ = ctx.Value(utils.RoleCtxKey).(*utils.RoleClaims)
+			return "ok", nil
+		}
+
+		if _, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/user.UserService/GetAllUsers"}, handler); err != nil {
+			t.Fatal(err)
+		}
+		if (got == nil) != (want == nil) || got != nil && *got != *want {
+			t.Errorf("role in context = %+v, want %+v", got, want)
+		}
+	}
+}
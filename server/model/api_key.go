@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// APIKey authenticates a service account. Only a hash of the key is stored;
+// Prefix is the public part that identifies it.
+type APIKey struct {
+	ID               uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	ServiceAccountID uint       `gorm:"not null;index" json:"service_account_id"`
+	Name             string     `gorm:"not null" json:"name"`
+	Prefix           string     `gorm:"type:varchar(16);unique;not null" json:"prefix"`
+	KeyHash          string     `gorm:"type:varchar(64);not null" json:"-"`
+	Scopes           string     `gorm:"type:text;not null" json:"scopes"`
+	LastUsedAt       *time.Time `gorm:"type:timestamptz" json:"last_used_at"`
+	RevokedAt        *time.Time `gorm:"type:timestamptz" json:"revoked_at"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
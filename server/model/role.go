@@ -3,9 +3,13 @@ package model
 import "time"
 
 type Role struct {
-	ID        uint        `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name      string      `json:"name"`
-	RoleRight []RoleRight `gorm:"foreignKey:RoleID;references:ID" json:"role_rights"`
-	CreatedAt time.Time   `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
+	ID          uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string `json:"name"`
+	MFARequired bool   `gorm:"column:mfa_required;not null;default:false" json:"mfa_required"`
+	// RightsVersion is incremented whenever the role's rights or name
+	// change, so tokens embedding an older version can be detected.
+	RightsVersion int         `gorm:"not null;default:1" json:"rights_version"`
+	RoleRight     []RoleRight `gorm:"foreignKey:RoleID;references:ID" json:"role_rights"`
+	CreatedAt     time.Time   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
 }
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+)
+
+// Session is one login of a user. It lives in Redis for as long as its
+// refresh token family can be used and is deleted on logout or revocation.
+type Session struct {
+	ID         string    `json:"id"`
+	UserID     uint      `json:"user_id"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	FamilyID   string    `json:"family_id"`
+	IssuedAt   time.Time `json:"issued_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiredAt  time.Time `json:"expired_at"`
+}
@@ -4,10 +4,21 @@ import (
 	"time"
 )
 
+// Token is an issued access/refresh pair. Only the token IDs are stored:
+// AccessToken and RefreshToken are set when the pair is issued, so it can
+// be handed to the client, and are never persisted.
 type Token struct {
-	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
-	AccessToken  string    `gorm:"type:varchar(500);unique_index;not_null" json:"access_token"`
-	RefreshToken string    `gorm:"type:varchar(500);unique_index;not_null" json:"refresh_token"`
-	UserID       uint      `gorm:"not null" json:"user_id"`
-	ExpiredAt    time.Time `gorm:"type:timestamptz;not_null" json:"expired_at"`
+	ID               uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	AccessToken      string     `gorm:"-" json:"-"`
+	RefreshToken     string     `gorm:"-" json:"-"`
+	AccessTokenID    string     `gorm:"type:varchar(36);unique;not null" json:"access_token_id"`
+	RefreshTokenID   string     `gorm:"type:varchar(36);unique;not null" json:"refresh_token_id"`
+	UserID           uint       `gorm:"not null" json:"user_id"`
+	FamilyID         string     `gorm:"type:varchar(36);index;not null" json:"family_id"`
+	SessionID        string     `gorm:"type:varchar(36);index;not null" json:"session_id"`
+	ExpiredAt        time.Time  `gorm:"type:timestamptz;not null" json:"expired_at"`
+	RefreshExpiredAt time.Time  `gorm:"type:timestamptz;not null" json:"refresh_expired_at"`
+	UsedAt           *time.Time `gorm:"type:timestamptz" json:"used_at"`
+	RevokedAt        *time.Time `gorm:"type:timestamptz" json:"revoked_at"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
 }
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// UserTOTP is a user's TOTP enrolment. It is pending until the first code
+// is verified, which sets EnabledAt.
+type UserTOTP struct {
+	UserID      uint       `gorm:"primaryKey" json:"user_id"`
+	Secret      string     `gorm:"type:varchar(64);not null" json:"-"`
+	EnabledAt   *time.Time `gorm:"type:timestamptz" json:"enabled_at"`
+	LastCounter int64      `gorm:"not null;default:0" json:"-"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
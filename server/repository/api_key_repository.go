@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"tablelink_project/server/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(apiKey *model.APIKey) error
+	GetAPIKeyByPrefix(prefix string) (*model.APIKey, error)
+	GetActiveAPIKeys(serviceAccountID uint) ([]model.APIKey, error)
+	RevokeAPIKey(serviceAccountID, keyID uint) (bool, error)
+	TouchAPIKey(keyID uint, usedAt time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+func (ar *apiKeyRepository) CreateAPIKey(apiKey *model.APIKey) error {
+	return ar.db.Create(apiKey).Error
+}
+
+// GetAPIKeyByPrefix returns the key with the given prefix, including
+// revoked keys.
+func (ar *apiKeyRepository) GetAPIKeyByPrefix(prefix string) (*model.APIKey, error) {
+	apiKey := &model.APIKey{}
+	err := ar.db.Where("prefix = ?", prefix).Take(apiKey).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return apiKey, nil
+}
+
+func (ar *apiKeyRepository) GetActiveAPIKeys(serviceAccountID uint) ([]model.APIKey, error) {
+	apiKeys := []model.APIKey{}
+	err := ar.db.Where("service_account_id = ? AND revoked_at IS NULL", serviceAccountID).
+		Order("id").
+		Find(&apiKeys).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return apiKeys, nil
+}
+
+// RevokeAPIKey revokes a live key of the service account. It reports false
+// when there is no such key.
+func (ar *apiKeyRepository) RevokeAPIKey(serviceAccountID, keyID uint) (bool, error) {
+	result := ar.db.Model(&model.APIKey{}).
+		Where("id = ? AND service_account_id = ? AND revoked_at IS NULL", keyID, serviceAccountID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (ar *apiKeyRepository) TouchAPIKey(keyID uint, usedAt time.Time) error {
+	return ar.db.Model(&model.APIKey{}).Where("id = ?", keyID).Update("last_used_at", usedAt).Error
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	loginFailurePrefix = "login_failures:"
+	loginLockoutPrefix = "login_lockout:"
+)
+
+// LoginAttemptRepository counts failed logins and stores lockouts per key,
+// where a key identifies an account or a client IP.
+type LoginAttemptRepository interface {
+	AddFailure(ctx context.Context, key string, window time.Duration) (int64, error)
+	Lock(ctx context.Context, key string, ttl time.Duration) error
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	Reset(ctx context.Context, key string) error
+}
+
+type loginAttemptRepository struct {
+	redisClient *redis.Client
+}
+
+func NewLoginAttemptRepository(redisClient *redis.Client) LoginAttemptRepository {
+	return &loginAttemptRepository{
+		redisClient: redisClient,
+	}
+}
+
+// AddFailure increments the failure counter of key and returns the new
+// count. The counter is forgotten once no failure happened for window.
+func (lr *loginAttemptRepository) AddFailure(ctx context.Context, key string, window time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := lr.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, loginFailurePrefix+key)
+		pipe.Expire(ctx, loginFailurePrefix+key, window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+func (lr *loginAttemptRepository) Lock(ctx context.Context, key string, ttl time.Duration) error {
+	return lr.redisClient.Set(ctx, loginLockoutPrefix+key, 1, ttl).Err()
+}
+
+// LockedFor returns how long key stays locked, or zero when it is not.
+func (lr *loginAttemptRepository) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := lr.redisClient.PTTL(ctx, loginLockoutPrefix+key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+// Reset clears both the failure counter and the lockout of key.
+func (lr *loginAttemptRepository) Reset(ctx context.Context, key string) error {
+	return lr.redisClient.Del(ctx, loginFailurePrefix+key, loginLockoutPrefix+key).Err()
+}
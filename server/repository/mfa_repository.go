@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"tablelink_project/server/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MFARepository interface {
+	GetTOTP(userID uint) (*model.UserTOTP, error)
+	SaveTOTP(totp *model.UserTOTP, recoveryCodeHashes []string) error
+	UseTOTPCounter(userID uint, counter int64) (bool, error)
+	UseRecoveryCode(userID uint, codeHash string) (bool, error)
+}
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) MFARepository {
+	return &mfaRepository{
+		db: db,
+	}
+}
+
+func (mr *mfaRepository) GetTOTP(userID uint) (*model.UserTOTP, error) {
+	totp := &model.UserTOTP{}
+	err := mr.db.Where("user_id = ?", userID).Take(totp).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return totp, nil
+}
+
+// SaveTOTP replaces the user's TOTP enrolment and recovery codes.
+func (mr *mfaRepository) SaveTOTP(totp *model.UserTOTP, recoveryCodeHashes []string) error {
+	return mr.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(totp).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ?", totp.UserID).Delete(&model.RecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+
+		codes := make([]model.RecoveryCode, 0, len(recoveryCodeHashes))
+		for _, codeHash := range recoveryCodeHashes {
+			codes = append(codes, model.RecoveryCode{UserID: totp.UserID, CodeHash: codeHash})
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseTOTPCounter records counter as the last accepted code period and
+// enables a pending enrolment. It reports false when a code of the same or
+// a later period was already accepted, so a code cannot be replayed.
+func (mr *mfaRepository) UseTOTPCounter(userID uint, counter int64) (bool, error) {
+	result := mr.db.Model(&model.UserTOTP{}).
+		Where("user_id = ? AND last_counter < ?", userID, counter).
+		Updates(map[string]interface{}{
+			"last_counter": counter,
+			"enabled_at":   gorm.Expr("COALESCE(enabled_at, ?)", time.Now()),
+			"updated_at":   time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used. It reports false
+// when the user has no such unused code.
+func (mr *mfaRepository) UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	result := mr.db.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
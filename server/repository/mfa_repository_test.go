@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"testing"
+
+	"tablelink_project/server/model"
+)
+
+func TestMFARepositoryUseTOTPCounter(t *testing.T) {
+	db := testDB(t, &model.UserTOTP{}, &model.RecoveryCode{})
+	repo := NewMFARepository(db)
+
+	err := repo.SaveTOTP(&model.UserTOTP{UserID: 1, Secret: "JBSWY3DPEHPK3PXP"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name    string
+		userID  uint
+		counter int64
+		used    bool
+	}{
+		{"first code", 1, 100, true},
+		{"replayed code", 1, 100, false},
+		{"code of an earlier period", 1, 99, false},
+		{"code of a later period", 1, 101, true},
+		{"user without enrolment", 2, 102, false},
+	}
+
+	for _, step := range steps {
+		used, err := repo.UseTOTPCounter(step.userID, step.counter)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if used != step.used {
+			t.Errorf("%s: UseTOTPCounter(%d, %d) = %v, want %v", step.name, step.userID, step.counter, used, step.used)
+		}
+	}
+
+	totp, err := repo.GetTOTP(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totp.EnabledAt == nil {
+		t.Error("accepted code did not enable the enrolment")
+	}
+	if totp.LastCounter != 101 {
+		t.Errorf("LastCounter = %d, want 101", totp.LastCounter)
+	}
+}
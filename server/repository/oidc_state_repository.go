@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const oidcStatePrefix = "oidc_state:"
+
+// OIDCLoginState is what is remembered between redirecting a user to the
+// identity provider and the provider redirecting them back.
+type OIDCLoginState struct {
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+}
+
+type OIDCStateRepository interface {
+	Save(ctx context.Context, state string, loginState OIDCLoginState, ttl time.Duration) error
+	Consume(ctx context.Context, state string) (*OIDCLoginState, error)
+}
+
+type oidcStateRepository struct {
+	redisClient *redis.Client
+}
+
+func NewOIDCStateRepository(redisClient *redis.Client) OIDCStateRepository {
+	return &oidcStateRepository{
+		redisClient: redisClient,
+	}
+}
+
+func (or *oidcStateRepository) Save(ctx context.Context, state string, loginState OIDCLoginState, ttl time.Duration) error {
+	data, err := json.Marshal(loginState)
+	if err != nil {
+		return err
+	}
+
+	return or.redisClient.Set(ctx, oidcStatePrefix+state, data, ttl).Err()
+}
+
+// Consume returns and deletes the login state, or returns redis.Nil when the
+// state is unknown, expired or was already used.
+func (or *oidcStateRepository) Consume(ctx context.Context, state string) (*OIDCLoginState, error) {
+	data, err := or.redisClient.GetDel(ctx, oidcStatePrefix+state).Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	loginState := &OIDCLoginState{}
+	err = json.Unmarshal(data, loginState)
+	if err != nil {
+		return nil, err
+	}
+
+	return loginState, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	passwordResetPrefix     = "password_reset:"
+	passwordResetUserPrefix = "password_reset_user:"
+)
+
+type PasswordResetRepository interface {
+	Save(ctx context.Context, tokenHash string, userID uint, ttl time.Duration) error
+	Lookup(ctx context.Context, tokenHash string) (uint, error)
+	Consume(ctx context.Context, tokenHash string) (uint, error)
+}
+
+type passwordResetRepository struct {
+	redisClient *redis.Client
+}
+
+func NewPasswordResetRepository(redisClient *redis.Client) PasswordResetRepository {
+	return &passwordResetRepository{
+		redisClient: redisClient,
+	}
+}
+
+// Save stores a reset token for the user, replacing any token issued to
+// them before.
+func (pr *passwordResetRepository) Save(ctx context.Context, tokenHash string, userID uint, ttl time.Duration) error {
+	userKey := passwordResetUserPrefix + strconv.FormatUint(uint64(userID), 10)
+
+	previous, err := pr.redisClient.Get(ctx, userKey).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+
+	_, err = pr.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if previous != "" {
+			pipe.Del(ctx, passwordResetPrefix+previous)
+		}
+		pipe.Set(ctx, passwordResetPrefix+tokenHash, userID, ttl)
+		pipe.Set(ctx, userKey, tokenHash, ttl)
+		return nil
+	})
+	return err
+}
+
+// Lookup returns the user a reset token was issued to without using it up,
+// or redis.Nil when the token is unknown or expired.
+func (pr *passwordResetRepository) Lookup(ctx context.Context, tokenHash string) (uint, error) {
+	userID, err := pr.redisClient.Get(ctx, passwordResetPrefix+tokenHash).Uint64()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(userID), nil
+}
+
+// Consume deletes the reset token and returns the user it was issued to, or
+// redis.Nil when the token is unknown, expired or already used.
+func (pr *passwordResetRepository) Consume(ctx context.Context, tokenHash string) (uint, error) {
+	userID, err := pr.redisClient.GetDel(ctx, passwordResetPrefix+tokenHash).Uint64()
+	if err != nil {
+		return 0, err
+	}
+
+	pr.redisClient.Del(ctx, passwordResetUserPrefix+strconv.FormatUint(userID, 10))
+	return uint(userID), nil
+}
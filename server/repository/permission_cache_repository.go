@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	permissionCachePrefix       = "role_rights:"
+	permissionGenerationPrefix  = "role_rights_generation:"
+	permissionInvalidateChannel = "role_permissions_invalidate"
+)
+
+type PermissionCacheRepository interface {
+	Get(ctx context.Context, roleID uint) ([]byte, error)
+	Generation(ctx context.Context, roleID uint) (int64, error)
+	Set(ctx context.Context, roleID uint, generation int64, value []byte, ttl time.Duration) (bool, error)
+	Delete(ctx context.Context, roleID uint) error
+	SubscribeInvalidations(ctx context.Context) <-chan uint
+}
+
+type permissionCacheRepository struct {
+	redisClient *redis.Client
+}
+
+func NewPermissionCacheRepository(redisClient *redis.Client) PermissionCacheRepository {
+	return &permissionCacheRepository{
+		redisClient: redisClient,
+	}
+}
+
+// Get returns the cached permissions of a role, or redis.Nil when the role
+// is not cached.
+func (pr *permissionCacheRepository) Get(ctx context.Context, roleID uint) ([]byte, error) {
+	return pr.redisClient.Get(ctx, permissionCacheKey(roleID)).Bytes()
+}
+
+// Generation counts how often a role has been invalidated. Read it before
+// loading the role so Set can tell whether the load is already outdated.
+func (pr *permissionCacheRepository) Generation(ctx context.Context, roleID uint) (int64, error) {
+	generation, err := pr.redisClient.Get(ctx, permissionGenerationKey(roleID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return generation, err
+}
+
+// Set caches the permissions of a role unless it has been invalidated since
+// generation was read, in which case it reports false and writes nothing.
+func (pr *permissionCacheRepository) Set(ctx context.Context, roleID uint, generation int64, value []byte, ttl time.Duration) (bool, error) {
+	generationKey := permissionGenerationKey(roleID)
+	stored := false
+	err := pr.redisClient.Watch(ctx, func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, generationKey).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		if current != generation {
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, permissionCacheKey(roleID), value, ttl)
+			return nil
+		})
+		stored = err == nil
+		return err
+	}, generationKey)
+	if errors.Is(err, redis.TxFailedErr) {
+		return false, nil
+	}
+	return stored, err
+}
+
+// Delete drops the cached permissions of a role, bumps its generation so
+// loads already in flight are not cached, and tells every server instance
+// to drop its in-process copy as well.
+func (pr *permissionCacheRepository) Delete(ctx context.Context, roleID uint) error {
+	_, err := pr.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, permissionGenerationKey(roleID))
+		pipe.Del(ctx, permissionCacheKey(roleID))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return pr.redisClient.Publish(ctx, permissionInvalidateChannel, roleID).Err()
+}
+
+// SubscribeInvalidations streams the IDs of roles invalidated by any server
+// instance until ctx is done.
+func (pr *permissionCacheRepository) SubscribeInvalidations(ctx context.Context) <-chan uint {
+	roleIDs := make(chan uint)
+	pubsub := pr.redisClient.Subscribe(ctx, permissionInvalidateChannel)
+
+	go func() {
+		defer close(roleIDs)
+
+		for message := range pubsub.Channel() {
+			roleID, err := strconv.ParseUint(message.Payload, 10, 64)
+			if err != nil {
+				continue
+			}
+			select {
+			case roleIDs <- uint(roleID):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		_ = pubsub.Close()
+	}()
+
+	return roleIDs
+}
+
+func permissionCacheKey(roleID uint) string {
+	return permissionCachePrefix + strconv.FormatUint(uint64(roleID), 10)
+}
+
+func permissionGenerationKey(roleID uint) string {
+	return permissionGenerationPrefix + strconv.FormatUint(uint64(roleID), 10)
+}
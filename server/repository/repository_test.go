@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// testDB opens TEST_DATABASE_DSN and returns a transaction whose search
+// path points at a fresh schema with the given models migrated. Everything
+// is rolled back when the test ends. Tests are skipped without a database.
+func testDB(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+
+	tx := db.Begin()
+	t.Cleanup(func() { tx.Rollback() })
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if err := tx.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Exec("SET LOCAL search_path TO " + schema).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+
+	return tx
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"tablelink_project/server/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	CreateRole(*model.Role) error
+	UpdateRole(*model.Role) error
+	IncrementRightsVersion(roleID uint) error
+	DeleteRole(roleID int) error
+	GetRoleByID(roleID int) (*model.Role, error)
+	GetAllRoles() ([]model.Role, error)
+	GetRoleRight(roleID int, section, route string) (*model.RoleRight, error)
+	SaveRoleRight(*model.RoleRight) error
+	DeleteRoleRight(roleRightID int) error
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{
+		db: db,
+	}
+}
+
+func (rr *roleRepository) CreateRole(role *model.Role) error {
+	role.UpdatedAt = time.Now()
+	role.CreatedAt = time.Now()
+	err := rr.db.Omit("RoleRight").Create(role).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (rr *roleRepository) UpdateRole(role *model.Role) error {
+	role.UpdatedAt = time.Now()
+	err := rr.db.Model(&model.Role{}).Where("id = ?", role.ID).Select("Name", "MFARequired", "UpdatedAt").Updates(role).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (rr *roleRepository) IncrementRightsVersion(roleID uint) error {
+	return rr.db.Model(&model.Role{}).Where("id = ?", roleID).Updates(map[string]interface{}{
+		"rights_version": gorm.Expr("rights_version + 1"),
+		"updated_at":     time.Now(),
+	}).Error
+}
+
+func (rr *roleRepository) DeleteRole(roleID int) error {
+	err := rr.db.Delete(&model.Role{}, roleID).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (rr *roleRepository) GetRoleByID(roleID int) (*model.Role, error) {
+	role := &model.Role{}
+	err := rr.db.Preload("RoleRight").First(role, roleID).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+func (rr *roleRepository) GetAllRoles() ([]model.Role, error) {
+	roles := []model.Role{}
+	err := rr.db.Preload("RoleRight").Order("id").Find(&roles).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (rr *roleRepository) GetRoleRight(roleID int, section, route string) (*model.RoleRight, error) {
+	roleRight := &model.RoleRight{}
+	err := rr.db.Where("role_id = ? AND section = ? AND route = ?", roleID, section, route).Take(roleRight).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return roleRight, nil
+}
+
+func (rr *roleRepository) SaveRoleRight(roleRight *model.RoleRight) error {
+	roleRight.UpdatedAt = time.Now()
+	if roleRight.ID == 0 {
+		roleRight.CreatedAt = time.Now()
+		return rr.db.Create(roleRight).Error
+	}
+	return rr.db.Model(roleRight).Updates(map[string]interface{}{
+		"r_create":   roleRight.RCreate,
+		"r_read":     roleRight.RRead,
+		"r_update":   roleRight.RUpdate,
+		"r_delete":   roleRight.RDelete,
+		"updated_at": roleRight.UpdatedAt,
+	}).Error
+}
+
+func (rr *roleRepository) DeleteRoleRight(roleRightID int) error {
+	err := rr.db.Delete(&model.RoleRight{}, roleRightID).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
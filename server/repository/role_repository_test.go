@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"tablelink_project/server/model"
+
+	"gorm.io/gorm"
+)
+
+func TestRoleRepositoryDeleteRoleInUse(t *testing.T) {
+	db := testDB(t, &model.Role{}, &model.RoleRight{}, &model.User{})
+	repo := NewRoleRepository(db)
+
+	role := &model.Role{Name: "editor"}
+	if err := repo.CreateRole(role); err != nil {
+		t.Fatal(err)
+	}
+	user := &model.User{Email: "jane@example.com", Password: "hash", RoleID: role.ID}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Users must be moved to another role first, so deleting the role must
+	// neither cascade to them nor succeed.
+	if err := repo.DeleteRole(int(role.ID)); !errors.Is(err, gorm.ErrForeignKeyViolated) {
+		t.Errorf("DeleteRole() error = %v, want %v", err, gorm.ErrForeignKeyViolated)
+	}
+}
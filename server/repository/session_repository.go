@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"tablelink_project/server/model"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	sessionPrefix     = "session:"
+	userSessionPrefix = "user_sessions:"
+)
+
+type SessionRepository interface {
+	Save(ctx context.Context, session *model.Session) error
+	Get(ctx context.Context, sessionID string) (*model.Session, error)
+	GetByUserID(ctx context.Context, userID uint) ([]model.Session, error)
+	Delete(ctx context.Context, session *model.Session) error
+}
+
+type sessionRepository struct {
+	redisClient *redis.Client
+}
+
+func NewSessionRepository(redisClient *redis.Client) SessionRepository {
+	return &sessionRepository{
+		redisClient: redisClient,
+	}
+}
+
+// Save stores the session until it expires and indexes it under its user.
+func (sr *sessionRepository) Save(ctx context.Context, session *model.Session) error {
+	ttl := time.Until(session.ExpiredAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	userKey := userSessionKey(session.UserID)
+	indexTTL, err := sr.redisClient.TTL(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+
+	_, err = sr.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, sessionPrefix+session.ID, data, ttl)
+		pipe.SAdd(ctx, userKey, session.ID)
+		// The index lives as long as the longest session it holds.
+		if indexTTL < ttl {
+			pipe.Expire(ctx, userKey, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// Get returns the session, or redis.Nil when it expired or was deleted.
+func (sr *sessionRepository) Get(ctx context.Context, sessionID string) (*model.Session, error) {
+	data, err := sr.redisClient.Get(ctx, sessionPrefix+sessionID).Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &model.Session{}
+	err = json.Unmarshal(data, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// GetByUserID returns the live sessions of the user and drops expired ones
+// from the index.
+func (sr *sessionRepository) GetByUserID(ctx context.Context, userID uint) ([]model.Session, error) {
+	userKey := userSessionKey(userID)
+	sessionIDs, err := sr.redisClient.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []model.Session{}
+	for _, sessionID := range sessionIDs {
+		session, err := sr.Get(ctx, sessionID)
+		if err == redis.Nil {
+			sr.redisClient.SRem(ctx, userKey, sessionID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+
+	return sessions, nil
+}
+
+func (sr *sessionRepository) Delete(ctx context.Context, session *model.Session) error {
+	_, err := sr.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, sessionPrefix+session.ID)
+		pipe.SRem(ctx, userSessionKey(session.UserID), session.ID)
+		return nil
+	})
+	return err
+}
+
+func userSessionKey(userID uint) string {
+	return userSessionPrefix + strconv.FormatUint(uint64(userID), 10)
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const tokenDenylistPrefix = "revoked_token:"
+
+type TokenDenylistRepository interface {
+	Add(ctx context.Context, tokenID string, ttl time.Duration) error
+	Exists(ctx context.Context, tokenID string) (bool, error)
+}
+
+type tokenDenylistRepository struct {
+	redisClient *redis.Client
+}
+
+func NewTokenDenylistRepository(redisClient *redis.Client) TokenDenylistRepository {
+	return &tokenDenylistRepository{
+		redisClient: redisClient,
+	}
+}
+
+func (tr *tokenDenylistRepository) Add(ctx context.Context, tokenID string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return tr.redisClient.Set(ctx, tokenDenylistPrefix+tokenID, 1, ttl).Err()
+}
+
+func (tr *tokenDenylistRepository) Exists(ctx context.Context, tokenID string) (bool, error) {
+	count, err := tr.redisClient.Exists(ctx, tokenDenylistPrefix+tokenID).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
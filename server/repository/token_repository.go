@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"tablelink_project/server/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TokenRepository interface {
+	CreateToken(*model.Token) error
+	GetTokenByAccessTokenID(tokenID string) (*model.Token, error)
+	GetTokenByRefreshTokenID(tokenID string) (*model.Token, error)
+	GetActiveTokensByUserID(userID int) ([]model.Token, error)
+	GetActiveTokensByFamilyID(familyID string) ([]model.Token, error)
+	MarkRefreshTokenUsed(tokenID uint) (bool, error)
+	RevokeToken(tokenID uint) error
+}
+
+type tokenRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRepository(db *gorm.DB) TokenRepository {
+	return &tokenRepository{
+		db: db,
+	}
+}
+
+func (tr *tokenRepository) CreateToken(token *model.Token) error {
+	token.CreatedAt = time.Now()
+	err := tr.db.Create(token).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tr *tokenRepository) GetTokenByAccessTokenID(tokenID string) (*model.Token, error) {
+	token := &model.Token{}
+	err := tr.db.Where("access_token_id = ?", tokenID).Take(token).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (tr *tokenRepository) GetTokenByRefreshTokenID(tokenID string) (*model.Token, error) {
+	token := &model.Token{}
+	err := tr.db.Where("refresh_token_id = ?", tokenID).Take(token).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (tr *tokenRepository) GetActiveTokensByUserID(userID int) ([]model.Token, error) {
+	tokens := []model.Token{}
+	err := tr.db.Where("user_id = ? AND revoked_at IS NULL AND refresh_expired_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (tr *tokenRepository) GetActiveTokensByFamilyID(familyID string) ([]model.Token, error) {
+	tokens := []model.Token{}
+	err := tr.db.Where("family_id = ? AND revoked_at IS NULL", familyID).Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// MarkRefreshTokenUsed flags the refresh token as consumed. It reports false
+// when the token had already been used, so concurrent replays cannot both win.
+func (tr *tokenRepository) MarkRefreshTokenUsed(tokenID uint) (bool, error) {
+	result := tr.db.Model(&model.Token{}).
+		Where("id = ? AND used_at IS NULL", tokenID).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (tr *tokenRepository) RevokeToken(tokenID uint) error {
+	err := tr.db.Model(&model.Token{}).
+		Where("id = ? AND revoked_at IS NULL", tokenID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
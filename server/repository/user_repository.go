@@ -1,19 +1,58 @@
 package repository
 
 import (
+	"fmt"
+	"strings"
 	"tablelink_project/server/model"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// UserOrderColumns maps the sortable user fields to the SQL expression they
+// are ordered by. Nullable columns are coalesced so keyset pagination never
+// compares against NULL.
+var UserOrderColumns = map[string]string{
+	"id":          "users.id",
+	"email":       "users.email",
+	"name":        "COALESCE(users.name, '')",
+	"last_access": "COALESCE(users.last_access, '0001-01-01'::timestamp)",
+	"created_at":  "users.created_at",
+}
+
+type UserFilter struct {
+	RoleID         uint
+	EmailPrefix    string
+	NameContains   string
+	LastAccessFrom *time.Time
+	LastAccessTo   *time.Time
+}
+
+// UserPage selects one page of users ordered by OrderBy and then by ID.
+// When HasCursor is set only users sorting after (AfterValue, AfterID) are
+// returned. AfterValue must have the Go type of the order column.
+type UserPage struct {
+	Filter     UserFilter
+	OrderBy    string
+	Descending bool
+	Limit      int
+	HasCursor  bool
+	AfterValue interface{}
+	AfterID    uint
+}
+
 type UserRepository interface {
 	CreateUser(model.User) error
 	UpdateUser(*model.User) error
+	UpdateUserFields(userID int, fields map[string]interface{}) error
+	ActivateUser(userID uint, hashedPassword string) (bool, error)
 	DeleteUser(userID int) error
 	GetUserByEmail(email string) (*model.User, error)
 	GetUserByID(userID int) (*model.User, error)
-	GetAllUsers() ([]model.User, error)
+	GetUserRoleID(userID int) (uint, error)
+	GetAllUsers(page UserPage) ([]model.User, error)
+	GetUsersByKind(kind string) ([]model.User, error)
+	CountUsers(filter UserFilter) (int64, error)
 }
 
 type userRepository struct {
@@ -45,6 +84,35 @@ func (ur *userRepository) UpdateUser(user *model.User) error {
 	return nil
 }
 
+// UpdateUserFields updates exactly the given columns, including zero values
+// that UpdateUser would skip.
+func (ur *userRepository) UpdateUserFields(userID int, fields map[string]interface{}) error {
+	fields["updated_at"] = time.Now()
+	err := ur.db.Model(&model.User{}).Where("id = ?", userID).Updates(fields).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ActivateUser sets the password of a pending user and activates them. It
+// reports false when the user is not pending, so an invite is only accepted
+// once.
+func (ur *userRepository) ActivateUser(userID uint, hashedPassword string) (bool, error) {
+	result := ur.db.Model(&model.User{}).
+		Where("id = ? AND status = ?", userID, model.UserStatusPending).
+		Updates(map[string]interface{}{
+			"password":   hashedPassword,
+			"status":     model.UserStatusActive,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
 func (ur *userRepository) DeleteUser(userID int) error {
 	err := ur.db.Delete(&model.User{}, userID).Error
 	if err != nil {
@@ -73,12 +141,87 @@ func (ur *userRepository) GetUserByID(userID int) (*model.User, error) {
 	return user, nil
 }
 
-func (ur *userRepository) GetAllUsers() ([]model.User, error) {
-	user := []model.User{}
-	err := ur.db.Preload("Role").Model(model.User{}).Take(&user).Error
+func (ur *userRepository) GetUserRoleID(userID int) (uint, error) {
+	user := &model.User{}
+	err := ur.db.Select("role_id").First(user, userID).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return user.RoleID, nil
+}
+
+func (ur *userRepository) GetAllUsers(page UserPage) ([]model.User, error) {
+	column, ok := UserOrderColumns[page.OrderBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid order column: %s", page.OrderBy)
+	}
+
+	direction, comparison := "ASC", ">"
+	if page.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	query := ur.filterUsers(page.Filter)
+	if page.HasCursor {
+		query = query.Where(
+			fmt.Sprintf("(%s, users.id) %s (?, ?)", column, comparison),
+			page.AfterValue, page.AfterID,
+		)
+	}
+
+	users := []model.User{}
+	err := query.Preload("Role").
+		Order(fmt.Sprintf("%s %s, users.id %s", column, direction, direction)).
+		Limit(page.Limit).
+		Find(&users).Error
 	if err != nil {
 		return nil, err
 	}
 
-	return user, nil
+	return users, nil
+}
+
+func (ur *userRepository) GetUsersByKind(kind string) ([]model.User, error) {
+	users := []model.User{}
+	err := ur.db.Preload("Role").Where("kind = ?", kind).Order("id").Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (ur *userRepository) CountUsers(filter UserFilter) (int64, error) {
+	var total int64
+	err := ur.filterUsers(filter).Count(&total).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (ur *userRepository) filterUsers(filter UserFilter) *gorm.DB {
+	query := ur.db.Model(&model.User{})
+	if filter.RoleID != 0 {
+		query = query.Where("users.role_id = ?", filter.RoleID)
+	}
+	if filter.EmailPrefix != "" {
+		query = query.Where("users.email LIKE ?", escapeLike(filter.EmailPrefix)+"%")
+	}
+	if filter.NameContains != "" {
+		query = query.Where("users.name ILIKE ?", "%"+escapeLike(filter.NameContains)+"%")
+	}
+	if filter.LastAccessFrom != nil {
+		query = query.Where("users.last_access >= ?", *filter.LastAccessFrom)
+	}
+	if filter.LastAccessTo != nil {
+		query = query.Where("users.last_access <= ?", *filter.LastAccessTo)
+	}
+	return query
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
 }
@@ -0,0 +1,42 @@
+package service
+
+import "strings"
+
+// FieldViolation describes why one request field is invalid.
+type FieldViolation struct {
+	Field       string
+	Reason      string
+	Description string
+}
+
+// ValidationError lists every invalid field of a request.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Field+": "+violation.Description)
+	}
+	return "invalid argument: " + strings.Join(messages, "; ")
+}
+
+// add records a violation of field.
+func (e *ValidationError) add(field, description string) {
+	e.Violations = append(e.Violations, FieldViolation{Field: field, Description: description})
+}
+
+// addReason records a violation of field with a machine-readable reason.
+func (e *ValidationError) addReason(field, reason, description string) {
+	e.Violations = append(e.Violations, FieldViolation{Field: field, Reason: reason, Description: description})
+}
+
+// orNil returns the error only when a violation was recorded, so it can be
+// returned directly from validation helpers.
+func (e *ValidationError) orNil() error {
+	if len(e.Violations) == 0 {
+		return nil
+	}
+	return e
+}
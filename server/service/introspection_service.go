@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"tablelink_project/server/model"
+	"tablelink_project/server/repository"
+	"tablelink_project/server/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TokenTypeHintAccessToken  = "access_token"
+	TokenTypeHintRefreshToken = "refresh_token"
+
+	tokenTypeBearer       = "Bearer"
+	tokenTypeRefreshToken = "refresh_token"
+	tokenTypeAPIKey       = "api_key"
+)
+
+// TokenIntrospection describes a token as RFC 7662 introspection reports
+// it. Every field but Active is empty for inactive tokens. Actor is the
+// subject of the user acting through an impersonation token.
+type TokenIntrospection struct {
+	Active    bool
+	TokenType string
+	Subject   string
+	Username  string
+	Role      string
+	RoleID    uint
+	Scopes    []string
+	ClientID  string
+	SessionID string
+	TokenID   string
+	Issuer    string
+	Audience  string
+	ExpiresAt int64
+	IssuedAt  int64
+	Actor     string
+}
+
+type IntrospectionService interface {
+	Introspect(ctx context.Context, token, tokenTypeHint string) (*TokenIntrospection, error)
+}
+
+type introspectionService struct {
+	tokenGenerator        utils.CredentialTokenGenerator
+	tokenService          TokenService
+	permissionService     PermissionService
+	serviceAccountService ServiceAccountService
+	userRepo              repository.UserRepository
+}
+
+func NewIntrospectionService(
+	tokenGenerator utils.CredentialTokenGenerator,
+	tokenService TokenService,
+	permissionService PermissionService,
+	serviceAccountService ServiceAccountService,
+	userRepo repository.UserRepository,
+) IntrospectionService {
+	return &introspectionService{
+		tokenGenerator:        tokenGenerator,
+		tokenService:          tokenService,
+		permissionService:     permissionService,
+		serviceAccountService: serviceAccountService,
+		userRepo:              userRepo,
+	}
+}
+
+// Introspect reports whether the access token, refresh token or API key is
+// active: validly signed, unexpired, not revoked, in a live session and
+// belonging to an active user. The hint only decides which kind of token
+// is tried first.
+func (ins *introspectionService) Introspect(ctx context.Context, token, tokenTypeHint string) (*TokenIntrospection, error) {
+	token = utils.TrimBearer(strings.TrimSpace(token))
+	if strings.HasPrefix(token, apiKeyScheme) {
+		return ins.introspectAPIKey(token)
+	}
+
+	introspectors := []func(context.Context, string) (*TokenIntrospection, error){
+		ins.introspectAccessToken,
+		ins.introspectRefreshToken,
+	}
+	if tokenTypeHint == TokenTypeHintRefreshToken {
+		introspectors[0], introspectors[1] = introspectors[1], introspectors[0]
+	}
+
+	for _, introspect := range introspectors {
+		introspection, err := introspect(ctx, token)
+		if err != nil || introspection.Active {
+			return introspection, err
+		}
+	}
+
+	return &TokenIntrospection{}, nil
+}
+
+func (ins *introspectionService) introspectAccessToken(ctx context.Context, token string) (*TokenIntrospection, error) {
+	claim, err := ins.tokenGenerator.ParseToken(token)
+	if err != nil {
+		return &TokenIntrospection{}, nil
+	}
+
+	revoked, err := ins.tokenService.IsTokenRevoked(ctx, claim.Id)
+	if err != nil || revoked {
+		return &TokenIntrospection{}, err
+	}
+	if claim.SessionID != "" {
+		active, err := ins.tokenService.IsSessionActive(ctx, claim.SessionID)
+		if err != nil || !active {
+			return &TokenIntrospection{}, err
+		}
+	}
+
+	introspection := &TokenIntrospection{
+		TokenType: tokenTypeBearer,
+		SessionID: claim.SessionID,
+		TokenID:   claim.Id,
+		Issuer:    claim.Issuer,
+		Audience:  claim.Audience,
+		ExpiresAt: claim.ExpiresAt,
+		IssuedAt:  claim.IssuedAt,
+	}
+	if claim.Actor != nil {
+		introspection.Actor = claim.Actor.Subject
+	}
+	return ins.withUser(ctx, introspection, claim.UserID)
+}
+
+func (ins *introspectionService) introspectRefreshToken(ctx context.Context, token string) (*TokenIntrospection, error) {
+	claim, err := ins.tokenGenerator.ParseRefreshToken(token)
+	if err != nil {
+		return &TokenIntrospection{}, nil
+	}
+
+	active, err := ins.tokenService.IsRefreshTokenActive(ctx, claim)
+	if err != nil || !active {
+		return &TokenIntrospection{}, err
+	}
+
+	introspection := &TokenIntrospection{
+		TokenType: tokenTypeRefreshToken,
+		SessionID: claim.SessionID,
+		TokenID:   claim.Id,
+		Issuer:    claim.Issuer,
+		Audience:  claim.Audience,
+		ExpiresAt: claim.ExpiresAt,
+		IssuedAt:  claim.IssuedAt,
+	}
+	return ins.withUser(ctx, introspection, claim.UserID)
+}
+
+func (ins *introspectionService) introspectAPIKey(apiKey string) (*TokenIntrospection, error) {
+	identity, err := ins.serviceAccountService.Authenticate(apiKey)
+	if errors.Is(err, ErrInvalidAPIKey) {
+		return &TokenIntrospection{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := ins.activeUser(identity.ServiceAccountID)
+	if err != nil || user == nil {
+		return &TokenIntrospection{}, err
+	}
+
+	return &TokenIntrospection{
+		Active:    true,
+		TokenType: tokenTypeAPIKey,
+		Subject:   strconv.FormatUint(uint64(user.ID), 10),
+		Username:  user.Email,
+		Role:      user.Role.Name,
+		RoleID:    user.RoleID,
+		Scopes:    identity.Scopes,
+		ClientID:  identity.Prefix,
+	}, nil
+}
+
+// withUser completes the introspection of a token issued to userID and
+// marks it active while the user is.
+func (ins *introspectionService) withUser(ctx context.Context, introspection *TokenIntrospection, userID uint) (*TokenIntrospection, error) {
+	user, err := ins.activeUser(userID)
+	if err != nil || user == nil {
+		return &TokenIntrospection{}, err
+	}
+
+	permissions, err := ins.permissionService.GetRolePermissions(ctx, user.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	introspection.Active = true
+	introspection.Subject = strconv.FormatUint(uint64(user.ID), 10)
+	introspection.Username = user.Email
+	introspection.Role = user.Role.Name
+	introspection.RoleID = user.RoleID
+	introspection.Scopes = permissions.Scopes()
+	return introspection, nil
+}
+
+// activeUser returns the user, or nil when they were deleted or are not
+// active.
+func (ins *introspectionService) activeUser(userID uint) (*model.User, error) {
+	user, err := ins.userRepo.GetUserByID(int(userID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Status != model.UserStatusActive {
+		return nil, nil
+	}
+
+	return user, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"tablelink_project/server/model"
+)
+
+func TestIntrospectionServiceIntrospect(t *testing.T) {
+	ctx := context.Background()
+	users := &memoryUsers{users: map[uint]*model.User{
+		1: {ID: 1, Email: "jane@example.com", Name: "Jane", RoleID: 2, Role: model.Role{ID: 2, Name: "editor"}, Status: model.UserStatusActive, Kind: model.UserKindHuman},
+		2: {ID: 2, Email: "john@example.com", Name: "John", RoleID: 2, Role: model.Role{ID: 2, Name: "editor"}, Status: model.UserStatusPending, Kind: model.UserKindHuman},
+		3: {ID: 3, Email: "billing@service-accounts.tablelink.local", Name: "billing", RoleID: 2, Role: model.Role{ID: 2, Name: "editor"}, Status: model.UserStatusActive, Kind: model.UserKindService},
+	}}
+	tokens := &memoryTokens{}
+	sessions := newMemorySessions()
+	tokenService := NewTokenService(tokens, newMemoryDenylist(), sessions, nil, nil, testTokenGenerator(), false)
+	serviceAccounts := NewServiceAccountService(users, &memoryRoles{ids: map[uint]bool{2: true}}, &memoryAPIKeys{}, nil)
+	introspection := NewIntrospectionService(
+		testTokenGenerator(),
+		tokenService,
+		&stubPermissions{permissions: RolePermissions{"users /users": rightRead | rightUpdate}},
+		serviceAccounts,
+		users,
+	)
+
+	issue := func(userID uint) *model.Token {
+		token, err := tokenService.IssueToken(ctx, userID, SessionClient{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	active := issue(1)
+	revoked := issue(1)
+	if err := tokenService.RevokeSession(ctx, 1, revoked.SessionID); err != nil {
+		t.Fatal(err)
+	}
+	rotated := issue(1)
+	claim, err := testTokenGenerator().ParseRefreshToken(rotated.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokenService.RotateToken(ctx, claim); err != nil {
+		t.Fatal(err)
+	}
+	pending := issue(2)
+	_, apiKey, err := serviceAccounts.CreateAPIKey(3, "ci", []string{"user.UserService"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		hint      string
+		active    bool
+		tokenType string
+	}{
+		{"access token", "Bearer " + active.AccessToken, "", true, tokenTypeBearer},
+		{"access token with refresh hint", active.AccessToken, TokenTypeHintRefreshToken, true, tokenTypeBearer},
+		{"refresh token", active.RefreshToken, TokenTypeHintRefreshToken, true, tokenTypeRefreshToken},
+		{"refresh token without hint", active.RefreshToken, "", true, tokenTypeRefreshToken},
+		{"api key", apiKey, "", true, tokenTypeAPIKey},
+		{"access token of a revoked session", revoked.AccessToken, "", false, ""},
+		{"refresh token of a revoked session", revoked.RefreshToken, TokenTypeHintRefreshToken, false, ""},
+		{"exchanged refresh token", rotated.RefreshToken, TokenTypeHintRefreshToken, false, ""},
+		{"token of a pending user", pending.AccessToken, "", false, ""},
+		{"unknown api key", apiKey + "x", "", false, ""},
+		{"malformed token", "nonsense", "", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := introspection.Introspect(ctx, tt.token, tt.hint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.active {
+				if !reflect.DeepEqual(got, &TokenIntrospection{}) {
+					t.Errorf("Introspect() = %+v, want only active false", got)
+				}
+				return
+			}
+			if !got.Active || got.TokenType != tt.tokenType || got.RoleID != 2 || got.Role != "editor" {
+				t.Errorf("Introspect() = %+v, want an active %s of role editor", got, tt.tokenType)
+			}
+		})
+	}
+
+	got, err := introspection.Introspect(ctx, active.AccessToken, TokenTypeHintAccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"users:/users:read", "users:/users:update"}
+	if got.Subject != "1" || got.Username != "jane@example.com" || got.SessionID != active.SessionID || got.TokenID != active.AccessTokenID || !reflect.DeepEqual(got.Scopes, want) {
+		t.Errorf("Introspect() = %+v, want user 1 in its session with scopes %v", got, want)
+	}
+
+	got, err = introspection.Introspect(ctx, apiKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Subject != "3" || got.Username != "billing@service-accounts.tablelink.local" || got.ClientID == "" || !reflect.DeepEqual(got.Scopes, []string{"user.UserService"}) {
+		t.Errorf("Introspect(api key) = %+v, want service account 3 with its key scopes", got)
+	}
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/mail"
+	"net/url"
+	"strings"
+	"tablelink_project/server/model"
+	"tablelink_project/server/repository"
+	"tablelink_project/server/utils"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidInviteToken = errors.New("invalid or expired invite token")
+
+type InviteService interface {
+	InviteUser(ctx context.Context, actorID uint, invite model.User) (*model.User, time.Time, error)
+	AcceptInvite(ctx context.Context, inviteToken, password string) error
+}
+
+type inviteService struct {
+	userRepo          repository.UserRepository
+	roleRepo          repository.RoleRepository
+	permissionService PermissionService
+	tokenGenerator    utils.CredentialTokenGenerator
+	mailer            utils.Mailer
+	passwordPolicy    PasswordPolicy
+	inviteURL         string
+}
+
+// NewInviteService creates an InviteService. The invite token is appended
+// to inviteURL in the email, so it should end with a query parameter such as
+// "https://app.example.com/invite?token=". Without one the bare token is
+// sent.
+func NewInviteService(
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	permissionService PermissionService,
+	tokenGenerator utils.CredentialTokenGenerator,
+	mailer utils.Mailer,
+	passwordPolicy PasswordPolicy,
+	inviteURL string,
+) InviteService {
+	return &inviteService{
+		userRepo:          userRepo,
+		roleRepo:          roleRepo,
+		permissionService: permissionService,
+		tokenGenerator:    tokenGenerator,
+		mailer:            mailer,
+		passwordPolicy:    passwordPolicy,
+		inviteURL:         inviteURL,
+	}
+}
+
+// InviteUser creates a pending user without a password and emails them an
+// invite token. Inviting a pending user again updates their name and role
+// and sends a new token, which invalidates the previous one. The role of
+// actorID must cover the rights of the role the user is invited to.
+func (is *inviteService) InviteUser(ctx context.Context, actorID uint, invite model.User) (*model.User, time.Time, error) {
+	invite.Email = html.EscapeString(strings.TrimSpace(invite.Email))
+	invite.Name = strings.TrimSpace(invite.Name)
+
+	existing, err := is.userRepo.GetUserByEmail(invite.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, time.Time{}, err
+	}
+
+	err = is.validateInvite(invite, existing)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	err = checkRoleCovered(ctx, is.userRepo, is.permissionService, actorID, invite.RoleID)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	invitedAt := time.Now().Truncate(time.Second)
+	if existing == nil {
+		invite.Status = model.UserStatusPending
+		invite.InvitedAt = &invitedAt
+		invite.Password = ""
+		err = is.userRepo.CreateUser(invite)
+	} else {
+		err = is.userRepo.UpdateUserFields(int(existing.ID), map[string]interface{}{
+			"name":       invite.Name,
+			"role_id":    invite.RoleID,
+			"invited_at": invitedAt,
+		})
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	user, err := is.userRepo.GetUserByEmail(invite.Email)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	inviteToken, expiredAt, err := is.tokenGenerator.GenerateInvite(user.ID, invitedAt)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	err = is.mailer.Send(utils.Mail{
+		To:      user.Email,
+		Subject: "You have been invited to tablelink",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nYou have been invited to tablelink. Choose your password to activate your account:\n\n%s\n\nThe invitation expires at %s.\n",
+			user.Name, is.inviteLink(inviteToken), expiredAt.UTC().Format(time.RFC1123),
+		),
+	})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	user, err = is.userRepo.GetUserByID(int(user.ID))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return user, expiredAt, nil
+}
+
+// AcceptInvite sets the invited user's password and activates them.
+func (is *inviteService) AcceptInvite(ctx context.Context, inviteToken, password string) error {
+	claim, err := is.tokenGenerator.ParseInvite(inviteToken)
+	if err != nil {
+		return ErrInvalidInviteToken
+	}
+
+	user, err := is.userRepo.GetUserByID(int(claim.UserID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidInviteToken
+	}
+	if err != nil {
+		return err
+	}
+	if user.Status != model.UserStatusPending || user.InvitedAt == nil || user.InvitedAt.Unix() != claim.InvitedAt {
+		return ErrInvalidInviteToken
+	}
+
+	err = validatePasswordPolicy(is.passwordPolicy, password, "password", *user)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	activated, err := is.userRepo.ActivateUser(user.ID, string(hashedPassword))
+	if err != nil {
+		return err
+	}
+	if !activated {
+		return ErrInvalidInviteToken
+	}
+
+	return nil
+}
+
+func (is *inviteService) validateInvite(invite model.User, existing *model.User) error {
+	validationErr := &ValidationError{}
+
+	address, err := mail.ParseAddress(invite.Email)
+	if err != nil || address.Address != invite.Email || len(invite.Email) > maxEmailLength {
+		validationErr.add("email", "must be a valid email address")
+	} else if existing != nil && existing.Status != model.UserStatusPending {
+		validationErr.add("email", "is already in use")
+	}
+
+	if invite.Name == "" {
+		validationErr.add("name", "must not be empty")
+	} else if utf8.RuneCountInString(invite.Name) > maxNameLength {
+		validationErr.add("name", fmt.Sprintf("must be at most %d characters", maxNameLength))
+	}
+
+	if _, err := is.roleRepo.GetRoleByID(int(invite.RoleID)); err != nil {
+		validationErr.add("role_id", "must reference an existing role")
+	}
+
+	return validationErr.orNil()
+}
+
+func (is *inviteService) inviteLink(inviteToken string) string {
+	if is.inviteURL == "" {
+		return inviteToken
+	}
+	return is.inviteURL + url.QueryEscape(inviteToken)
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"tablelink_project/server/model"
+	"tablelink_project/server/repository"
+	"tablelink_project/server/utils"
+
+	"github.com/golang-jwt/jwt"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	testInviteURL = "https://app.example.com/invite?token="
+	// testInviter is the user sending invites, in role 1.
+	testInviter uint = 100
+)
+
+// memoryUsers implements the UserRepository methods the service tests use.
+// Any other method panics through the nil embedded interface.
+type memoryUsers struct {
+	repository.UserRepository
+	users map[uint]*model.User
+}
+
+func (m *memoryUsers) CreateUser(user model.User) error {
+	user.ID = uint(len(m.users) + 1)
+	m.users[user.ID] = &user
+	return nil
+}
+
+func (m *memoryUsers) UpdateUserFields(userID int, fields map[string]interface{}) error {
+	user, ok := m.users[uint(userID)]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	for column, value := range fields {
+		switch column {
+		case "name":
+			user.Name = value.(string)
+		case "role_id":
+			user.RoleID = value.(uint)
+		case "invited_at":
+			invitedAt := value.(time.Time)
+			user.InvitedAt = &invitedAt
+		}
+	}
+	return nil
+}
+
+func (m *memoryUsers) ActivateUser(userID uint, hashedPassword string) (bool, error) {
+	user, ok := m.users[userID]
+	if !ok || user.Status != model.UserStatusPending {
+		return false, nil
+	}
+	user.Password = hashedPassword
+	user.Status = model.UserStatusActive
+	return true, nil
+}
+
+func (m *memoryUsers) GetUserByEmail(email string) (*model.User, error) {
+	for _, user := range m.users {
+		if user.Email == email {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memoryUsers) GetUserByID(userID int) (*model.User, error) {
+	user, ok := m.users[uint(userID)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *user
+	return &found, nil
+}
+
+func (m *memoryUsers) GetUserRoleID(userID int) (uint, error) {
+	user, ok := m.users[uint(userID)]
+	if !ok {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return user.RoleID, nil
+}
+
+func (m *memoryUsers) CountUsers(filter repository.UserFilter) (int64, error) {
+	var count int64
+	for _, user := range m.users {
+		if filter.RoleID == 0 || user.RoleID == filter.RoleID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// memoryRoles implements RoleRepository.GetRoleByID for a fixed set of role
+// ids.
+type memoryRoles struct {
+	repository.RoleRepository
+	ids map[uint]bool
+}
+
+func (m *memoryRoles) GetRoleByID(roleID int) (*model.Role, error) {
+	if !m.ids[uint(roleID)] {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &model.Role{ID: uint(roleID)}, nil
+}
+
+type inviteFixture struct {
+	service InviteService
+	users   *memoryUsers
+	mailer  *utils.MemoryMailer
+	tokens  *utils.JWT
+}
+
+func newInviteFixture(t *testing.T) *inviteFixture {
+	t.Helper()
+
+	key, err := utils.NewHMACKey("test", jwt.SigningMethodHS256, "test-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring, err := utils.NewKeyring([]*utils.SigningKey{key}, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixture := &inviteFixture{
+		users: &memoryUsers{users: map[uint]*model.User{
+			testInviter: {ID: testInviter, Email: "admin@example.com", RoleID: 1, Status: model.UserStatusActive},
+		}},
+		mailer: utils.NewMemoryMailer(),
+		tokens: utils.NewJWT(utils.JWTConfig{Keyring: ring}),
+	}
+	fixture.service = NewInviteService(
+		fixture.users,
+		&memoryRoles{ids: map[uint]bool{1: true, 2: true}},
+		&stubPermissions{roles: map[uint]RolePermissions{
+			1: {"users /users": rightRead, "users /users/user": rightCreate},
+			2: {"users /users": rightRead | rightDelete},
+		}},
+		fixture.tokens,
+		fixture.mailer,
+		NewRulePasswordPolicy(10, 3, nil),
+		testInviteURL,
+	)
+	return fixture
+}
+
+// inviteToken extracts the invite token from the link in an invite email.
+func inviteToken(t *testing.T, mail utils.Mail) string {
+	t.Helper()
+
+	_, link, ok := strings.Cut(mail.Body, testInviteURL)
+	if !ok {
+		t.Fatalf("invite email has no invite link: %q", mail.Body)
+	}
+	link, _, _ = strings.Cut(link, "\n")
+	token, err := url.QueryUnescape(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestInviteServiceInviteUserValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		invite model.User
+		fields []string
+	}{
+		{"invalid email", model.User{Email: "jane", Name: "Jane", RoleID: 1}, []string{"email"}},
+		{"display name email", model.User{Email: "Jane <jane@example.com>", Name: "Jane", RoleID: 1}, []string{"email"}},
+		{"empty name", model.User{Email: "jane@example.com", Name: "  ", RoleID: 1}, []string{"name"}},
+		{"unknown role", model.User{Email: "jane@example.com", Name: "Jane", RoleID: 3}, []string{"role_id"}},
+		{"everything", model.User{}, []string{"email", "name", "role_id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixture := newInviteFixture(t)
+
+			_, _, err := fixture.service.InviteUser(context.Background(), testInviter, tt.invite)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("InviteUser() error = %v, want ValidationError", err)
+			}
+			var fields []string
+			for _, violation := range validationErr.Violations {
+				fields = append(fields, violation.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("violated fields = %v, want %v", fields, tt.fields)
+			}
+			if sent := fixture.mailer.Sent(); len(sent) != 0 {
+				t.Errorf("sent %d emails for an invalid invite", len(sent))
+			}
+		})
+	}
+}
+
+func TestInviteServiceInviteUserRoleNotCovered(t *testing.T) {
+	fixture := newInviteFixture(t)
+
+	_, _, err := fixture.service.InviteUser(context.Background(), testInviter, model.User{Email: "jane@example.com", Name: "Jane", RoleID: 2})
+	if !errors.Is(err, ErrRoleNotCovered) {
+		t.Fatalf("InviteUser() error = %v, want %v", err, ErrRoleNotCovered)
+	}
+	if _, err := fixture.users.GetUserByEmail("jane@example.com"); err == nil {
+		t.Error("invited user was created")
+	}
+	if sent := fixture.mailer.Sent(); len(sent) != 0 {
+		t.Errorf("sent %d emails for a refused invite", len(sent))
+	}
+}
+
+func TestInviteServiceInviteAndAccept(t *testing.T) {
+	ctx := context.Background()
+	fixture := newInviteFixture(t)
+
+	user, expiredAt, err := fixture.service.InviteUser(ctx, testInviter, model.User{Email: " jane@example.com ", Name: "Jane", RoleID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Status != model.UserStatusPending || user.InvitedAt == nil || user.Password != "" {
+		t.Errorf("invited user = %+v, want a pending user without password", user)
+	}
+	if !expiredAt.After(time.Now()) {
+		t.Errorf("invite expires at %s, want a future time", expiredAt)
+	}
+
+	sent := fixture.mailer.Sent()
+	if len(sent) != 1 || sent[0].To != "jane@example.com" {
+		t.Fatalf("sent emails = %+v, want one to jane@example.com", sent)
+	}
+	token := inviteToken(t, sent[0])
+
+	tests := []struct {
+		name     string
+		token    string
+		password string
+		wantErr  error
+	}{
+		{"malformed token", "not-a-token", "Tr0ub4dor&3x", ErrInvalidInviteToken},
+		{"weak password", token, "short", &ValidationError{}},
+		{"accepted", token, "Tr0ub4dor&3x", nil},
+		{"accepted twice", token, "Tr0ub4dor&3x", ErrInvalidInviteToken},
+	}
+
+	for _, tt := range tests {
+		err := fixture.service.AcceptInvite(ctx, tt.token, tt.password)
+		switch want := tt.wantErr.(type) {
+		case nil:
+			if err != nil {
+				t.Fatalf("%s: AcceptInvite() error = %v", tt.name, err)
+			}
+		case *ValidationError:
+			if !errors.As(err, &want) {
+				t.Errorf("%s: AcceptInvite() error = %v, want ValidationError", tt.name, err)
+			}
+		default:
+			if !errors.Is(err, want) {
+				t.Errorf("%s: AcceptInvite() error = %v, want %v", tt.name, err, want)
+			}
+		}
+	}
+
+	activated := fixture.users.users[user.ID]
+	if activated.Status != model.UserStatusActive {
+		t.Errorf("status = %s, want active", activated.Status)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(activated.Password), []byte("Tr0ub4dor&3x")) != nil {
+		t.Error("stored password does not match the accepted one")
+	}
+
+	// An active account cannot be invited again.
+	_, _, err = fixture.service.InviteUser(ctx, testInviter, model.User{Email: "jane@example.com", Name: "Jane", RoleID: 1})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("InviteUser(active user) error = %v, want ValidationError", err)
+	}
+}
+
+func TestInviteServiceReinviteInvalidatesEarlierToken(t *testing.T) {
+	ctx := context.Background()
+	fixture := newInviteFixture(t)
+
+	user, _, err := fixture.service.InviteUser(ctx, testInviter, model.User{Email: "jane@example.com", Name: "Jane", RoleID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Invitations are stored to the second, so move the first one an hour
+	// into the past instead of waiting for the clock to tick.
+	earlier := user.InvitedAt.Add(-time.Hour)
+	fixture.users.users[user.ID].InvitedAt = &earlier
+	earlierToken, _, err := fixture.tokens.GenerateInvite(user.ID, earlier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reinvited, _, err := fixture.service.InviteUser(ctx, testInviter, model.User{Email: "jane@example.com", Name: "Jane Doe", RoleID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reinvited.ID != user.ID || reinvited.Name != "Jane Doe" || reinvited.Status != model.UserStatusPending {
+		t.Errorf("re-invited user = %+v, want pending user %d renamed", reinvited, user.ID)
+	}
+
+	sent := fixture.mailer.Sent()
+	if len(sent) != 2 {
+		t.Fatalf("sent %d emails, want 2", len(sent))
+	}
+
+	if err := fixture.service.AcceptInvite(ctx, earlierToken, "Tr0ub4dor&3x"); !errors.Is(err, ErrInvalidInviteToken) {
+		t.Errorf("AcceptInvite(earlier token) error = %v, want ErrInvalidInviteToken", err)
+	}
+	if err := fixture.service.AcceptInvite(ctx, inviteToken(t, sent[1]), "Tr0ub4dor&3x"); err != nil {
+		t.Errorf("AcceptInvite(latest token) error = %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"tablelink_project/server/repository"
+	"time"
+)
+
+// LoginThrottleConfig configures when failed logins lock an account or a
+// client IP. Zero values fall back to the defaults.
+type LoginThrottleConfig struct {
+	// MaxAccountFailures is how many failures an account may have before it
+	// is locked.
+	MaxAccountFailures int
+	// MaxIPFailures is how many failures a client IP may have, across all
+	// accounts, before it is locked.
+	MaxIPFailures int
+	// BaseLockout is the first lockout. Every further failure doubles it up
+	// to MaxLockout.
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+	// FailureWindow is how long failures are remembered after the last one.
+	FailureWindow time.Duration
+}
+
+// ThrottledError is returned while an account or client IP is locked out.
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, retry after %d seconds", retryAfterSeconds(e.RetryAfter))
+}
+
+// RetryAfterSeconds rounds RetryAfter up to whole seconds.
+func (e *ThrottledError) RetryAfterSeconds() int64 {
+	return retryAfterSeconds(e.RetryAfter)
+}
+
+type LoginThrottle interface {
+	Check(ctx context.Context, email, ip string) error
+	RecordFailure(ctx context.Context, email, ip string) error
+	RecordSuccess(ctx context.Context, email string) error
+	Unlock(ctx context.Context, email string) error
+}
+
+type loginThrottle struct {
+	loginAttemptRepo repository.LoginAttemptRepository
+	config           LoginThrottleConfig
+}
+
+func NewLoginThrottle(loginAttemptRepo repository.LoginAttemptRepository, config LoginThrottleConfig) LoginThrottle {
+	if config.MaxAccountFailures <= 0 {
+		config.MaxAccountFailures = 5
+	}
+	if config.MaxIPFailures <= 0 {
+		config.MaxIPFailures = 50
+	}
+	if config.BaseLockout <= 0 {
+		config.BaseLockout = time.Minute
+	}
+	if config.MaxLockout <= 0 {
+		config.MaxLockout = time.Hour
+	}
+	if config.FailureWindow <= 0 {
+		config.FailureWindow = time.Hour
+	}
+
+	return &loginThrottle{
+		loginAttemptRepo: loginAttemptRepo,
+		config:           config,
+	}
+}
+
+// Check returns a ThrottledError when either the account or the client IP
+// is locked out.
+func (lt *loginThrottle) Check(ctx context.Context, email, ip string) error {
+	var retryAfter time.Duration
+	for _, key := range lt.keys(email, ip) {
+		lockedFor, err := lt.loginAttemptRepo.LockedFor(ctx, key)
+		if err != nil {
+			return err
+		}
+		if lockedFor > retryAfter {
+			retryAfter = lockedFor
+		}
+	}
+
+	if retryAfter > 0 {
+		return &ThrottledError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed login against the account and the client
+// IP and locks whichever reached its limit.
+func (lt *loginThrottle) RecordFailure(ctx context.Context, email, ip string) error {
+	if err := lt.addFailure(ctx, accountKey(email), lt.config.MaxAccountFailures); err != nil {
+		return err
+	}
+	if ip == "" {
+		return nil
+	}
+	return lt.addFailure(ctx, ipKey(ip), lt.config.MaxIPFailures)
+}
+
+// RecordSuccess forgets the failures of the account. The client IP keeps
+// its count so one valid account cannot be used to reset it.
+func (lt *loginThrottle) RecordSuccess(ctx context.Context, email string) error {
+	return lt.loginAttemptRepo.Reset(ctx, accountKey(email))
+}
+
+func (lt *loginThrottle) Unlock(ctx context.Context, email string) error {
+	return lt.loginAttemptRepo.Reset(ctx, accountKey(email))
+}
+
+func (lt *loginThrottle) addFailure(ctx context.Context, key string, maxFailures int) error {
+	failures, err := lt.loginAttemptRepo.AddFailure(ctx, key, lt.config.FailureWindow)
+	if err != nil {
+		return err
+	}
+	if failures < int64(maxFailures) {
+		return nil
+	}
+
+	return lt.loginAttemptRepo.Lock(ctx, key, lt.lockout(failures-int64(maxFailures)))
+}
+
+// lockout doubles BaseLockout for every failure past the limit.
+func (lt *loginThrottle) lockout(excess int64) time.Duration {
+	lockout := float64(lt.config.BaseLockout) * math.Pow(2, float64(excess))
+	if lockout > float64(lt.config.MaxLockout) {
+		return lt.config.MaxLockout
+	}
+	return time.Duration(lockout)
+}
+
+func (lt *loginThrottle) keys(email, ip string) []string {
+	keys := []string{accountKey(email)}
+	if ip != "" {
+		keys = append(keys, ipKey(ip))
+	}
+	return keys
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+func retryAfterSeconds(retryAfter time.Duration) int64 {
+	return int64(math.Ceil(retryAfter.Seconds()))
+}
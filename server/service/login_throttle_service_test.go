@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// memoryLoginAttempts is an in-memory LoginAttemptRepository. Failure
+// windows are not expired since the tests never wait for them.
+type memoryLoginAttempts struct {
+	failures map[string]int64
+	locks    map[string]time.Duration
+}
+
+func newMemoryLoginAttempts() *memoryLoginAttempts {
+	return &memoryLoginAttempts{
+		failures: map[string]int64{},
+		locks:    map[string]time.Duration{},
+	}
+}
+
+func (m *memoryLoginAttempts) AddFailure(ctx context.Context, key string, window time.Duration) (int64, error) {
+	m.failures[key]++
+	return m.failures[key], nil
+}
+
+func (m *memoryLoginAttempts) Lock(ctx context.Context, key string, ttl time.Duration) error {
+	m.locks[key] = ttl
+	return nil
+}
+
+func (m *memoryLoginAttempts) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	return m.locks[key], nil
+}
+
+func (m *memoryLoginAttempts) Reset(ctx context.Context, key string) error {
+	delete(m.failures, key)
+	delete(m.locks, key)
+	return nil
+}
+
+func TestLoginThrottleLockout(t *testing.T) {
+	config := LoginThrottleConfig{
+		MaxAccountFailures: 3,
+		MaxIPFailures:      10,
+		BaseLockout:        time.Minute,
+		MaxLockout:         5 * time.Minute,
+	}
+
+	tests := []struct {
+		name       string
+		failures   int
+		retryAfter time.Duration
+	}{
+		{"below limit", 2, 0},
+		{"at limit", 3, time.Minute},
+		{"one past limit doubles", 4, 2 * time.Minute},
+		{"two past limit doubles again", 5, 4 * time.Minute},
+		{"capped at max lockout", 8, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			throttle := NewLoginThrottle(newMemoryLoginAttempts(), config)
+
+			for i := 0; i < tt.failures; i++ {
+				if err := throttle.RecordFailure(ctx, "jane@example.com", "10.0.0.1"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := throttle.Check(ctx, "Jane@Example.com ", "10.0.0.2")
+			assertRetryAfter(t, err, tt.retryAfter)
+		})
+	}
+}
+
+func TestLoginThrottleIPLockout(t *testing.T) {
+	ctx := context.Background()
+	throttle := NewLoginThrottle(newMemoryLoginAttempts(), LoginThrottleConfig{
+		MaxAccountFailures: 3,
+		MaxIPFailures:      3,
+		BaseLockout:        time.Minute,
+	})
+
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		if err := throttle.RecordFailure(ctx, email, "10.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assertRetryAfter(t, throttle.Check(ctx, "d@example.com", "10.0.0.1"), time.Minute)
+	assertRetryAfter(t, throttle.Check(ctx, "d@example.com", "10.0.0.2"), 0)
+
+	// A successful login only forgets the account's failures.
+	if err := throttle.RecordSuccess(ctx, "d@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	assertRetryAfter(t, throttle.Check(ctx, "d@example.com", "10.0.0.1"), time.Minute)
+}
+
+func TestLoginThrottleUnlock(t *testing.T) {
+	ctx := context.Background()
+	throttle := NewLoginThrottle(newMemoryLoginAttempts(), LoginThrottleConfig{MaxAccountFailures: 1})
+
+	if err := throttle.RecordFailure(ctx, "jane@example.com", ""); err != nil {
+		t.Fatal(err)
+	}
+	assertRetryAfter(t, throttle.Check(ctx, "jane@example.com", ""), time.Minute)
+
+	if err := throttle.Unlock(ctx, "JANE@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	assertRetryAfter(t, throttle.Check(ctx, "jane@example.com", ""), 0)
+}
+
+func TestThrottledErrorRetryAfterSeconds(t *testing.T) {
+	tests := []struct {
+		retryAfter time.Duration
+		seconds    int64
+	}{
+		{time.Minute, 60},
+		{1500 * time.Millisecond, 2},
+		{time.Millisecond, 1},
+	}
+
+	for _, tt := range tests {
+		err := &ThrottledError{RetryAfter: tt.retryAfter}
+		if got := err.RetryAfterSeconds(); got != tt.seconds {
+			t.Errorf("RetryAfterSeconds(%s) = %d, want %d", tt.retryAfter, got, tt.seconds)
+		}
+	}
+}
+
+func assertRetryAfter(t *testing.T, err error, retryAfter time.Duration) {
+	t.Helper()
+
+	if retryAfter == 0 {
+		if err != nil {
+			t.Errorf("Check() error = %v, want nil", err)
+		}
+		return
+	}
+
+	var throttled *ThrottledError
+	if !errors.As(err, &throttled) {
+		t.Fatalf("Check() error = %v, want ThrottledError", err)
+	}
+	if throttled.RetryAfter != retryAfter {
+		t.Errorf("RetryAfter = %s, want %s", throttled.RetryAfter, retryAfter)
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"tablelink_project/server/model"
+	"tablelink_project/server/repository"
+	"tablelink_project/server/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultTOTPIssuer = "tablelink"
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrMFAAlreadyEnabled = errors.New("mfa is already enabled")
+	ErrMFANotEnrolled    = errors.New("mfa is not enrolled")
+	ErrInvalidMFACode    = errors.New("invalid mfa code")
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTPEnrollment is returned once when a user enrols TOTP. The recovery
+// codes are only stored hashed and cannot be shown again.
+type TOTPEnrollment struct {
+	Secret        string
+	URI           string
+	RecoveryCodes []string
+}
+
+type MFAService interface {
+	EnrollTOTP(ctx context.Context, userID uint) (*TOTPEnrollment, error)
+	VerifyTOTP(ctx context.Context, userID uint, code string) error
+	// LoginRequirement reports whether the user must pass MFA to log in and
+	// whether they still have to enrol first.
+	LoginRequirement(user *model.User) (required bool, enrolled bool, err error)
+}
+
+type mfaService struct {
+	userRepo repository.UserRepository
+	roleRepo repository.RoleRepository
+	mfaRepo  repository.MFARepository
+	totp     *utils.TOTP
+	issuer   string
+}
+
+func NewMFAService(
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	mfaRepo repository.MFARepository,
+	totp *utils.TOTP,
+	issuer string,
+) MFAService {
+	if issuer == "" {
+		issuer = defaultTOTPIssuer
+	}
+
+	return &mfaService{
+		userRepo: userRepo,
+		roleRepo: roleRepo,
+		mfaRepo:  mfaRepo,
+		totp:     totp,
+		issuer:   issuer,
+	}
+}
+
+// EnrollTOTP starts a TOTP enrolment with a new secret and recovery codes,
+// replacing any pending one. It stays pending until VerifyTOTP accepts a
+// code.
+func (ms *mfaService) EnrollTOTP(ctx context.Context, userID uint) (*TOTPEnrollment, error) {
+	user, err := ms.userRepo.GetUserByID(int(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := ms.mfaRepo.GetTOTP(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil && existing.EnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := ms.totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment := &TOTPEnrollment{
+		Secret: secret,
+		URI:    ms.totp.URI(ms.issuer, user.Email, secret),
+	}
+	codeHashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		enrollment.RecoveryCodes = append(enrollment.RecoveryCodes, code)
+		codeHashes = append(codeHashes, hashToken(normalizeMFACode(code)))
+	}
+
+	err = ms.mfaRepo.SaveTOTP(&model.UserTOTP{UserID: userID, Secret: secret}, codeHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return enrollment, nil
+}
+
+// VerifyTOTP accepts a current TOTP code, enabling a pending enrolment, or
+// one of the recovery codes of an enabled enrolment. Every code is accepted
+// only once.
+func (ms *mfaService) VerifyTOTP(ctx context.Context, userID uint, code string) error {
+	totp, err := ms.mfaRepo.GetTOTP(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrMFANotEnrolled
+	}
+	if err != nil {
+		return err
+	}
+
+	code = normalizeMFACode(code)
+	if counter, ok := ms.totp.Validate(totp.Secret, code); ok {
+		used, err := ms.mfaRepo.UseTOTPCounter(userID, counter)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	if totp.EnabledAt == nil {
+		return ErrInvalidMFACode
+	}
+
+	used, err := ms.mfaRepo.UseRecoveryCode(userID, hashToken(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (ms *mfaService) LoginRequirement(user *model.User) (bool, bool, error) {
+	totp, err := ms.mfaRepo.GetTOTP(user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, false, err
+	}
+	if totp != nil && totp.EnabledAt != nil {
+		return true, true, nil
+	}
+
+	role, err := ms.roleRepo.GetRoleByID(int(user.RoleID))
+	if err != nil {
+		return false, false, err
+	}
+
+	return role.MFARequired, false, nil
+}
+
+// normalizeMFACode strips the separators users may type along with a code.
+func normalizeMFACode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
+
+// generateRecoveryCode returns a random code formatted as xxxxx-xxxxx.
+func generateRecoveryCode() (string, error) {
+	random := make([]byte, 10)
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(recoveryCodeEncoding.EncodeToString(random))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
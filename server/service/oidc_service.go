@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"tablelink_project/server/model"
+	"tablelink_project/server/repository"
+	"tablelink_project/server/utils"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+// oidcStateTTL is how long a user has to sign in at the identity provider.
+const oidcStateTTL = 10 * time.Minute
+
+var (
+	ErrInvalidOIDCState   = errors.New("invalid or expired oidc state")
+	ErrOIDCIdentityDenied = errors.New("identity provider login failed")
+	ErrOIDCUserNotFound   = errors.New("no user is registered with this email")
+)
+
+// OIDCProvisioning controls what happens when a verified identity has no
+// user. With JIT set, an active user with DefaultRoleID is created for it.
+type OIDCProvisioning struct {
+	JIT           bool
+	DefaultRoleID uint
+}
+
+type OIDCService interface {
+	StartLogin(ctx context.Context) (string, error)
+	CompleteLogin(ctx context.Context, state, code string, client SessionClient) (*LoginResult, error)
+}
+
+type oidcService struct {
+	provider     *utils.OIDCProvider
+	stateRepo    repository.OIDCStateRepository
+	userRepo     repository.UserRepository
+	userService  UserService
+	provisioning OIDCProvisioning
+}
+
+func NewOIDCService(
+	provider *utils.OIDCProvider,
+	stateRepo repository.OIDCStateRepository,
+	userRepo repository.UserRepository,
+	userService UserService,
+	provisioning OIDCProvisioning,
+) OIDCService {
+	return &oidcService{
+		provider:     provider,
+		stateRepo:    stateRepo,
+		userRepo:     userRepo,
+		userService:  userService,
+		provisioning: provisioning,
+	}
+}
+
+// StartLogin returns the identity provider URL to redirect the user to. The
+// PKCE verifier and the nonce stay on the server under a random state.
+func (oidc *oidcService) StartLogin(ctx context.Context) (string, error) {
+	state, err := randomOIDCValue()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomOIDCValue()
+	if err != nil {
+		return "", err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	err = oidc.stateRepo.Save(ctx, state, repository.OIDCLoginState{
+		Verifier: verifier,
+		Nonce:    nonce,
+	}, oidcStateTTL)
+	if err != nil {
+		return "", err
+	}
+
+	return oidc.provider.AuthCodeURL(ctx, state, nonce, verifier)
+}
+
+// CompleteLogin redeems the code the identity provider redirected back with
+// and logs in the user with the verified email, like a password login
+// would.
+func (oidc *oidcService) CompleteLogin(ctx context.Context, state, code string, client SessionClient) (*LoginResult, error) {
+	loginState, err := oidc.stateRepo.Consume(ctx, state)
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrInvalidOIDCState
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := oidc.provider.Exchange(ctx, code, loginState.Verifier, loginState.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCIdentityDenied, err)
+	}
+
+	user, err := oidc.findOrProvisionUser(identity)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status == model.UserStatusPending {
+		return nil, ErrUserNotActive
+	}
+
+	return oidc.userService.LoginVerifiedUser(ctx, user, client)
+}
+
+func (oidc *oidcService) findOrProvisionUser(identity *utils.OIDCIdentity) (*model.User, error) {
+	email := html.EscapeString(strings.TrimSpace(identity.Email))
+
+	user, err := oidc.userRepo.GetUserByEmail(email)
+	if err == nil && user.Kind == model.UserKindService {
+		return nil, ErrOIDCUserNotFound
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, err
+	}
+	if !oidc.provisioning.JIT {
+		return nil, ErrOIDCUserNotFound
+	}
+
+	name := strings.TrimSpace(identity.Name)
+	if name == "" {
+		name = email
+	}
+
+	// Provisioned users have no password and can only sign in through the
+	// identity provider until they reset it.
+	err = oidc.userRepo.CreateUser(model.User{
+		Email:  email,
+		Name:   name,
+		RoleID: oidc.provisioning.DefaultRoleID,
+		Status: model.UserStatusActive,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return oidc.userRepo.GetUserByEmail(email)
+}
+
+func randomOIDCValue() (string, error) {
+	value := make([]byte, 32)
+	_, err := rand.Read(value)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(value), nil
+}
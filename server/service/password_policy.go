@@ -0,0 +1,136 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"tablelink_project/server/model"
+	"unicode"
+)
+
+// bcryptMaxBytes is the longest password bcrypt hashes; anything past it is
+// silently ignored, so longer passwords are rejected instead.
+const bcryptMaxBytes = 72
+
+// PasswordViolation is one password rule a password breaks.
+type PasswordViolation struct {
+	Rule        string
+	Description string
+}
+
+// PasswordPolicy decides whether a password may be used by a user.
+type PasswordPolicy interface {
+	Validate(password string, user model.User) []PasswordViolation
+}
+
+// RulePasswordPolicy is the configurable PasswordPolicy used by default.
+type RulePasswordPolicy struct {
+	MinLength           int
+	MaxBytes            int
+	MinCharacterClasses int
+	Denylist            map[string]struct{}
+}
+
+// NewRulePasswordPolicy creates a policy with sane defaults for zero values.
+func NewRulePasswordPolicy(minLength, minCharacterClasses int, denylist map[string]struct{}) *RulePasswordPolicy {
+	if minLength <= 0 {
+		minLength = 8
+	}
+	if denylist == nil {
+		denylist = map[string]struct{}{}
+	}
+
+	return &RulePasswordPolicy{
+		MinLength:           minLength,
+		MaxBytes:            bcryptMaxBytes,
+		MinCharacterClasses: minCharacterClasses,
+		Denylist:            denylist,
+	}
+}
+
+func (p *RulePasswordPolicy) Validate(password string, user model.User) []PasswordViolation {
+	violations := []PasswordViolation{}
+
+	if len([]rune(password)) < p.MinLength {
+		violations = append(violations, PasswordViolation{
+			Rule:        "PASSWORD_TOO_SHORT",
+			Description: fmt.Sprintf("must be at least %d characters", p.MinLength),
+		})
+	}
+	if len(password) > p.MaxBytes {
+		violations = append(violations, PasswordViolation{
+			Rule:        "PASSWORD_TOO_LONG",
+			Description: fmt.Sprintf("must be at most %d bytes", p.MaxBytes),
+		})
+	}
+	if classes := characterClasses(password); classes < p.MinCharacterClasses {
+		violations = append(violations, PasswordViolation{
+			Rule:        "PASSWORD_TOO_SIMPLE",
+			Description: fmt.Sprintf("must mix at least %d of lowercase, uppercase, digits and symbols", p.MinCharacterClasses),
+		})
+	}
+	if _, denied := p.Denylist[strings.ToLower(password)]; denied {
+		violations = append(violations, PasswordViolation{
+			Rule:        "PASSWORD_TOO_COMMON",
+			Description: "is too common",
+		})
+	}
+	if user.Email != "" && strings.EqualFold(password, user.Email) {
+		violations = append(violations, PasswordViolation{
+			Rule:        "PASSWORD_EQUALS_EMAIL",
+			Description: "must not be the email address",
+		})
+	}
+
+	return violations
+}
+
+// validatePasswordPolicy reports every rule of policy the password breaks
+// as a violation of field.
+func validatePasswordPolicy(policy PasswordPolicy, password, field string, user model.User) error {
+	validationErr := &ValidationError{}
+	for _, violation := range policy.Validate(password, user) {
+		validationErr.addReason(field, violation.Rule, violation.Description)
+	}
+	return validationErr.orNil()
+}
+
+// LoadPasswordDenylist reads one denied password per line, ignoring blank
+// lines and lines starting with #.
+func LoadPasswordDenylist(path string) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	denylist := map[string]struct{}{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		denylist[strings.ToLower(line)] = struct{}{}
+	}
+
+	return denylist, scanner.Err()
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"tablelink_project/server/model"
+)
+
+func TestRulePasswordPolicyValidate(t *testing.T) {
+	policy := NewRulePasswordPolicy(10, 3, map[string]struct{}{"correcthorse1!": {}})
+	user := model.User{Email: "Jane.Doe1@Example.com"}
+
+	tests := []struct {
+		name     string
+		password string
+		rules    []string
+	}{
+		{"strong", "Tr0ub4dor&3x", nil},
+		{"too short", "Ab1!", []string{"PASSWORD_TOO_SHORT"}},
+		{"length counts characters not bytes", "Äbcdéfgh1!", nil},
+		{"too long", "Aa1!" + strings.Repeat("a", 69), []string{"PASSWORD_TOO_LONG"}},
+		{"too simple", "abcdefghijkl", []string{"PASSWORD_TOO_SIMPLE"}},
+		{"denied case-insensitively", "CorrectHorse1!", []string{"PASSWORD_TOO_COMMON"}},
+		{"equals email", "jane.doe1@EXAMPLE.com", []string{"PASSWORD_EQUALS_EMAIL"}},
+		{"several rules", "abc", []string{"PASSWORD_TOO_SHORT", "PASSWORD_TOO_SIMPLE"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rules []string
+			for _, violation := range policy.Validate(tt.password, user) {
+				rules = append(rules, violation.Rule)
+			}
+			if !reflect.DeepEqual(rules, tt.rules) {
+				t.Errorf("Validate(%q) = %v, want %v", tt.password, rules, tt.rules)
+			}
+		})
+	}
+}
+
+func TestNewRulePasswordPolicyDefaults(t *testing.T) {
+	policy := NewRulePasswordPolicy(0, 0, nil)
+	if policy.MinLength != 8 || policy.MaxBytes != bcryptMaxBytes || policy.Denylist == nil {
+		t.Errorf("NewRulePasswordPolicy(0, 0, nil) = %+v", policy)
+	}
+}
+
+func TestValidatePasswordPolicyReportsField(t *testing.T) {
+	err := validatePasswordPolicy(NewRulePasswordPolicy(8, 0, nil), "short", "new_password", model.User{})
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("validatePasswordPolicy() = %v, want a ValidationError", err)
+	}
+	if len(validationErr.Violations) != 1 || validationErr.Violations[0].Field != "new_password" || validationErr.Violations[0].Reason != "PASSWORD_TOO_SHORT" {
+		t.Errorf("Violations = %+v", validationErr.Violations)
+	}
+
+	if err := validatePasswordPolicy(NewRulePasswordPolicy(8, 0, nil), "long enough", "new_password", model.User{}); err != nil {
+		t.Errorf("validatePasswordPolicy() = %v, want nil", err)
+	}
+}
+
+func TestLoadPasswordDenylist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "denylist.txt")
+	err := os.WriteFile(path, []byte("# common passwords\nPassword1\n\n  qwerty  \n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	denylist, err := LoadPasswordDenylist(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]struct{}{"password1": {}, "qwerty": {}}
+	if !reflect.DeepEqual(denylist, want) {
+		t.Errorf("LoadPasswordDenylist() = %v, want %v", denylist, want)
+	}
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"tablelink_project/server/repository"
+	"tablelink_project/server/utils"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	permissionLocalCacheSize = 1024
+	permissionLocalCacheTTL  = time.Minute
+	permissionRedisCacheTTL  = 30 * time.Minute
+)
+
+const (
+	rightCreate = 1 << iota
+	rightRead
+	rightUpdate
+	rightDelete
+)
+
+var (
+	permissionCacheMetrics = expvar.NewMap("permission_cache")
+	rightActions           = []struct {
+		right int
+		name  string
+	}{
+		{rightCreate, "create"},
+		{rightRead, "read"},
+		{rightUpdate, "update"},
+		{rightDelete, "delete"},
+	}
+	methodRights = map[string]int{
+		http.MethodPost:   rightCreate,
+		http.MethodGet:    rightRead,
+		http.MethodPut:    rightUpdate,
+		http.MethodDelete: rightDelete,
+	}
+)
+
+func init() {
+	permissionCacheMetrics.Set("hit_rate", expvar.Func(func() interface{} {
+		hits := metricValue("local_hits") + metricValue("redis_hits")
+		total := hits + metricValue("misses")
+		if total == 0 {
+			return 0.0
+		}
+		return float64(hits) / float64(total)
+	}))
+}
+
+// RolePermissions is a role's rights compiled into a lookup table from
+// "section route" to a bitmask of the allowed actions.
+type RolePermissions map[string]int
+
+// Allows reports whether the HTTP method is allowed on the section route.
+func (rp RolePermissions) Allows(section, route, method string) bool {
+	right, ok := methodRights[method]
+	return ok && rp[section+" "+route]&right != 0
+}
+
+// Covers reports whether every action allowed by other is also allowed by
+// rp.
+func (rp RolePermissions) Covers(other RolePermissions) bool {
+	for key, rights := range other {
+		if rp[key]&rights != rights {
+			return false
+		}
+	}
+	return true
+}
+
+// ErrRoleNotCovered is returned when a caller assigns a role, or manages a
+// user whose role, allows an action the caller's own role does not.
+var ErrRoleNotCovered = errors.New("role has rights the caller's role lacks")
+
+// checkRoleCovered returns ErrRoleNotCovered unless the role of actorID
+// allows every action roleID allows, so callers cannot reach rights beyond
+// their own by handing out roles or acting as more privileged users.
+func checkRoleCovered(ctx context.Context, userRepo repository.UserRepository, permissionService PermissionService, actorID, roleID uint) error {
+	actorRoleID, err := userRepo.GetUserRoleID(int(actorID))
+	if err != nil {
+		return err
+	}
+	if actorRoleID == roleID {
+		return nil
+	}
+
+	actorPermissions, err := permissionService.GetRolePermissions(ctx, actorRoleID)
+	if err != nil {
+		return err
+	}
+	rolePermissions, err := permissionService.GetRolePermissions(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	if !actorPermissions.Covers(rolePermissions) {
+		return ErrRoleNotCovered
+	}
+	return nil
+}
+
+// Scopes lists every allowed action as a section:route:action entry, such
+// as "user:/users:read", in a stable order.
+func (rp RolePermissions) Scopes() []string {
+	scopes := []string{}
+	for key, rights := range rp {
+		section, route, _ := strings.Cut(key, " ")
+		for _, action := range rightActions {
+			if rights&action.right != 0 {
+				scopes = append(scopes, section+":"+route+":"+action.name)
+			}
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// Digest is a short hash of the permissions that changes whenever any
+// allowed action does.
+func (rp RolePermissions) Digest() string {
+	sum := sha256.Sum256([]byte(strings.Join(rp.Scopes(), " ")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// RoleRights is a role's compiled permissions along with the name and
+// rights version of the role they were compiled from.
+type RoleRights struct {
+	Name        string          `json:"name"`
+	Version     int             `json:"version"`
+	Permissions RolePermissions `json:"permissions"`
+}
+
+type PermissionService interface {
+	GetRoleRights(ctx context.Context, roleID uint) (*RoleRights, error)
+	GetRolePermissions(ctx context.Context, roleID uint) (RolePermissions, error)
+	InvalidateRole(ctx context.Context, roleID uint) error
+	WatchInvalidations(ctx context.Context)
+}
+
+type permissionService struct {
+	roleRepo   repository.RoleRepository
+	cacheRepo  repository.PermissionCacheRepository
+	localCache *utils.LRU[uint, *RoleRights]
+}
+
+func NewPermissionService(roleRepo repository.RoleRepository, cacheRepo repository.PermissionCacheRepository) PermissionService {
+	return &permissionService{
+		roleRepo:   roleRepo,
+		cacheRepo:  cacheRepo,
+		localCache: utils.NewLRU[uint, *RoleRights](permissionLocalCacheSize, permissionLocalCacheTTL),
+	}
+}
+
+// GetRoleRights looks the role up in the in-process cache, then in Redis,
+// and only compiles it from the database on a miss in both.
+func (ps *permissionService) GetRoleRights(ctx context.Context, roleID uint) (*RoleRights, error) {
+	if rights, ok := ps.localCache.Get(roleID); ok {
+		permissionCacheMetrics.Add("local_hits", 1)
+		return rights, nil
+	}
+
+	cached, err := ps.cacheRepo.Get(ctx, roleID)
+	if err == nil {
+		rights := &RoleRights{}
+		if json.Unmarshal(cached, rights) == nil {
+			permissionCacheMetrics.Add("redis_hits", 1)
+			ps.localCache.Set(roleID, rights)
+			return rights, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		log.Printf("permission cache unavailable: %v", err)
+	}
+
+	permissionCacheMetrics.Add("misses", 1)
+	// Read the generation before the role so a rights change racing with
+	// the load is not cached until the TTL expires.
+	generation, generationErr := ps.cacheRepo.Generation(ctx, roleID)
+	if generationErr != nil {
+		log.Printf("permission cache unavailable: %v", generationErr)
+	}
+
+	role, err := ps.roleRepo.GetRoleByID(int(roleID))
+	if err != nil {
+		return nil, err
+	}
+
+	rights := &RoleRights{
+		Name:        role.Name,
+		Version:     role.RightsVersion,
+		Permissions: RolePermissions{},
+	}
+	for _, roleRight := range role.RoleRight {
+		key := roleRight.Section + " " + roleRight.Route
+		if roleRight.RCreate == 1 {
+			rights.Permissions[key] |= rightCreate
+		}
+		if roleRight.RRead == 1 {
+			rights.Permissions[key] |= rightRead
+		}
+		if roleRight.RUpdate == 1 {
+			rights.Permissions[key] |= rightUpdate
+		}
+		if roleRight.RDelete == 1 {
+			rights.Permissions[key] |= rightDelete
+		}
+	}
+
+	// Rights loaded while the role was invalidated are served but not
+	// cached. Without Redis they are still cached in-process.
+	stored := true
+	if encoded, err := json.Marshal(rights); err == nil && generationErr == nil {
+		stored, err = ps.cacheRepo.Set(ctx, roleID, generation, encoded, permissionRedisCacheTTL)
+		if err != nil {
+			log.Printf("failed to cache permissions of role %d: %v", roleID, err)
+			stored = true
+		}
+	}
+	if stored {
+		ps.localCache.Set(roleID, rights)
+	}
+
+	return rights, nil
+}
+
+func (ps *permissionService) GetRolePermissions(ctx context.Context, roleID uint) (RolePermissions, error) {
+	rights, err := ps.GetRoleRights(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rights.Permissions, nil
+}
+
+// InvalidateRole drops the compiled permissions of a role after its rights
+// changed, on this and every other server instance.
+func (ps *permissionService) InvalidateRole(ctx context.Context, roleID uint) error {
+	ps.localCache.Delete(roleID)
+	permissionCacheMetrics.Add("invalidations", 1)
+	return ps.cacheRepo.Delete(ctx, roleID)
+}
+
+// WatchInvalidations evicts roles invalidated by other server instances
+// from the in-process cache until ctx is done.
+func (ps *permissionService) WatchInvalidations(ctx context.Context) {
+	go func() {
+		for roleID := range ps.cacheRepo.SubscribeInvalidations(ctx) {
+			ps.localCache.Delete(roleID)
+		}
+	}()
+}
+
+func metricValue(name string) int64 {
+	if value, ok := permissionCacheMetrics.Get(name).(*expvar.Int); ok {
+		return value.Value()
+	}
+	return 0
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"tablelink_project/server/model"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// memoryPermissionCache is an in-memory PermissionCacheRepository.
+type memoryPermissionCache struct {
+	values      map[uint][]byte
+	generations map[uint]int64
+	sets        int
+}
+
+func (m *memoryPermissionCache) Get(ctx context.Context, roleID uint) ([]byte, error) {
+	value, ok := m.values[roleID]
+	if !ok {
+		return nil, redis.Nil
+	}
+	return value, nil
+}
+
+func (m *memoryPermissionCache) Generation(ctx context.Context, roleID uint) (int64, error) {
+	return m.generations[roleID], nil
+}
+
+func (m *memoryPermissionCache) Set(ctx context.Context, roleID uint, generation int64, value []byte, ttl time.Duration) (bool, error) {
+	if m.generations[roleID] != generation {
+		return false, nil
+	}
+	m.sets++
+	m.values[roleID] = value
+	return true, nil
+}
+
+func (m *memoryPermissionCache) Delete(ctx context.Context, roleID uint) error {
+	if m.generations == nil {
+		m.generations = map[uint]int64{}
+	}
+	m.generations[roleID]++
+	delete(m.values, roleID)
+	return nil
+}
+
+// invalidatingRoles invalidates the role it loads as if its rights changed
+// while the permissions were being compiled.
+type invalidatingRoles struct {
+	*memoryRoleRights
+	cache *memoryPermissionCache
+}
+
+func (r *invalidatingRoles) GetRoleByID(roleID int) (*model.Role, error) {
+	role, err := r.memoryRoleRights.GetRoleByID(roleID)
+	_ = r.cache.Delete(context.Background(), uint(roleID))
+	return role, err
+}
+
+func (m *memoryPermissionCache) SubscribeInvalidations(ctx context.Context) <-chan uint {
+	return make(chan uint)
+}
+
+func TestRolePermissionsAllows(t *testing.T) {
+	permissions := RolePermissions{"users /users": rightRead | rightDelete}
+
+	tests := []struct {
+		section, route, method string
+		want                   bool
+	}{
+		{"users", "/users", http.MethodGet, true},
+		{"users", "/users", http.MethodDelete, true},
+		{"users", "/users", http.MethodPost, false},
+		{"users", "/users", http.MethodPatch, false},
+		{"roles", "/users", http.MethodGet, false},
+		{"users", "/roles", http.MethodGet, false},
+	}
+
+	for _, tt := range tests {
+		if got := permissions.Allows(tt.section, tt.route, tt.method); got != tt.want {
+			t.Errorf("Allows(%s, %s, %s) = %v, want %v", tt.section, tt.route, tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestRolePermissionsCovers(t *testing.T) {
+	permissions := RolePermissions{"users /users": rightRead | rightDelete, "roles /roles": rightRead}
+
+	tests := []struct {
+		name  string
+		other RolePermissions
+		want  bool
+	}{
+		{"no rights", RolePermissions{}, true},
+		{"same rights", RolePermissions{"users /users": rightRead | rightDelete, "roles /roles": rightRead}, true},
+		{"fewer actions", RolePermissions{"users /users": rightRead}, true},
+		{"other action", RolePermissions{"users /users": rightRead | rightCreate}, false},
+		{"other route", RolePermissions{"users /users/user": rightRead}, false},
+	}
+
+	for _, tt := range tests {
+		if got := permissions.Covers(tt.other); got != tt.want {
+			t.Errorf("%s: Covers(%v) = %v, want %v", tt.name, tt.other, got, tt.want)
+		}
+	}
+}
+
+func TestPermissionServiceGetRolePermissions(t *testing.T) {
+	ctx := context.Background()
+	roles := &memoryRoleRights{role: &model.Role{ID: 1, RoleRight: []model.RoleRight{
+		{RoleID: 1, Section: "users", Route: "/users", RRead: 1},
+		{RoleID: 1, Section: "users", Route: "/users/user", RCreate: 1, RUpdate: 1},
+	}}}
+	cache := &memoryPermissionCache{values: map[uint][]byte{}}
+	service := NewPermissionService(roles, cache)
+
+	permissions, err := service.GetRolePermissions(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := RolePermissions{"users /users": rightRead, "users /users/user": rightCreate | rightUpdate}
+	if len(permissions) != len(want) || permissions["users /users"] != want["users /users"] || permissions["users /users/user"] != want["users /users/user"] {
+		t.Errorf("GetRolePermissions() = %v, want %v", permissions, want)
+	}
+	if cache.sets != 1 {
+		t.Fatalf("cached %d times, want 1", cache.sets)
+	}
+
+	// Served from the in-process cache, even though the role changed.
+	roles.role.RoleRight = nil
+	permissions, err = service.GetRolePermissions(ctx, 1)
+	if err != nil || !permissions.Allows("users", "/users", http.MethodGet) {
+		t.Errorf("GetRolePermissions() = %v, %v, want the cached permissions", permissions, err)
+	}
+
+	// Another instance with an empty in-process cache reads Redis.
+	permissions, err = NewPermissionService(roles, cache).GetRolePermissions(ctx, 1)
+	if err != nil || !permissions.Allows("users", "/users", http.MethodGet) {
+		t.Errorf("GetRolePermissions() = %v, %v, want the Redis permissions", permissions, err)
+	}
+
+	if err := service.InvalidateRole(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	permissions, err = service.GetRolePermissions(ctx, 1)
+	if err != nil || len(permissions) != 0 {
+		t.Errorf("GetRolePermissions() after InvalidateRole = %v, %v, want none", permissions, err)
+	}
+
+	if _, err := service.GetRolePermissions(ctx, 2); err == nil {
+		t.Error("GetRolePermissions(unknown role) succeeded, want error")
+	}
+}
+
+func TestPermissionServiceGetRolePermissionsInvalidatedDuringLoad(t *testing.T) {
+	ctx := context.Background()
+	cache := &memoryPermissionCache{values: map[uint][]byte{}}
+	roles := &invalidatingRoles{
+		memoryRoleRights: &memoryRoleRights{role: &model.Role{ID: 1, RoleRight: []model.RoleRight{
+			{RoleID: 1, Section: "users", Route: "/users", RRead: 1},
+		}}},
+		cache: cache,
+	}
+	service := NewPermissionService(roles, cache)
+
+	permissions, err := service.GetRolePermissions(ctx, 1)
+	if err != nil || !permissions.Allows("users", "/users", http.MethodGet) {
+		t.Fatalf("GetRolePermissions() = %v, %v, want the loaded permissions", permissions, err)
+	}
+	if cache.sets != 0 {
+		t.Errorf("cached %d times, want the outdated load not cached", cache.sets)
+	}
+
+	// Not cached in-process either, so the new rights are loaded.
+	roles.role.RoleRight = nil
+	permissions, err = service.GetRolePermissions(ctx, 1)
+	if err != nil || len(permissions) != 0 {
+		t.Errorf("GetRolePermissions() = %v, %v, want the new permissions", permissions, err)
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"tablelink_project/server/model"
+	"tablelink_project/server/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleNameTaken = errors.New("role name is already taken")
+	ErrRoleInUse     = errors.New("role is still assigned to users")
+)
+
+type RoleService interface {
+	CreateRole(*model.Role) error
+	UpdateRole(ctx context.Context, role *model.Role, mfaRequired *bool) error
+	DeleteRole(ctx context.Context, roleID int) error
+	GetRoleByID(roleID int) (*model.Role, error)
+	GetAllRoles() ([]model.Role, error)
+	GrantRoleRight(ctx context.Context, grant model.RoleRight) (*model.RoleRight, error)
+	RevokeRoleRight(ctx context.Context, revoke model.RoleRight) error
+}
+
+type roleService struct {
+	roleRepo          repository.RoleRepository
+	userRepo          repository.UserRepository
+	permissionService PermissionService
+}
+
+func NewRoleService(roleRepo repository.RoleRepository, userRepo repository.UserRepository, permissionService PermissionService) RoleService {
+	return &roleService{
+		roleRepo:          roleRepo,
+		userRepo:          userRepo,
+		permissionService: permissionService,
+	}
+}
+
+func (rs *roleService) CreateRole(role *model.Role) error {
+	err := rs.roleRepo.CreateRole(role)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrRoleNameTaken
+	}
+	return err
+}
+
+// UpdateRole renames the role and sets whether it requires MFA, keeping the
+// current requirement when mfaRequired is nil. Renaming changes the rights
+// version, since access tokens can embed the role name.
+func (rs *roleService) UpdateRole(ctx context.Context, role *model.Role, mfaRequired *bool) error {
+	existing, err := rs.roleRepo.GetRoleByID(int(role.ID))
+	if err != nil {
+		return err
+	}
+
+	role.MFARequired = existing.MFARequired
+	if mfaRequired != nil {
+		role.MFARequired = *mfaRequired
+	}
+
+	err = rs.roleRepo.UpdateRole(role)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrRoleNameTaken
+	}
+	if err != nil {
+		return err
+	}
+
+	if role.Name != existing.Name {
+		return rs.changeRights(ctx, role.ID)
+	}
+	return nil
+}
+
+// DeleteRole deletes a role that no user or service account is assigned
+// to. Users have to be moved to another role first.
+func (rs *roleService) DeleteRole(ctx context.Context, roleID int) error {
+	_, err := rs.roleRepo.GetRoleByID(roleID)
+	if err != nil {
+		return err
+	}
+
+	assigned, err := rs.userRepo.CountUsers(repository.UserFilter{RoleID: uint(roleID)})
+	if err != nil {
+		return err
+	}
+	if assigned > 0 {
+		return ErrRoleInUse
+	}
+
+	err = rs.roleRepo.DeleteRole(roleID)
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return ErrRoleInUse
+	}
+	if err != nil {
+		return err
+	}
+
+	return rs.permissionService.InvalidateRole(ctx, uint(roleID))
+}
+
+func (rs *roleService) GetRoleByID(roleID int) (*model.Role, error) {
+	return rs.roleRepo.GetRoleByID(roleID)
+}
+
+func (rs *roleService) GetAllRoles() ([]model.Role, error) {
+	return rs.roleRepo.GetAllRoles()
+}
+
+// GrantRoleRight allows every action set to 1 in grant on its section route,
+// creating the role right when the role had none for that route yet.
+func (rs *roleService) GrantRoleRight(ctx context.Context, grant model.RoleRight) (*model.RoleRight, error) {
+	_, err := rs.roleRepo.GetRoleByID(int(grant.RoleID))
+	if err != nil {
+		return nil, err
+	}
+
+	roleRight, err := rs.roleRepo.GetRoleRight(int(grant.RoleID), grant.Section, grant.Route)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		roleRight = &model.RoleRight{
+			RoleID:  grant.RoleID,
+			Section: grant.Section,
+			Route:   grant.Route,
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	roleRight.RCreate |= grant.RCreate
+	roleRight.RRead |= grant.RRead
+	roleRight.RUpdate |= grant.RUpdate
+	roleRight.RDelete |= grant.RDelete
+
+	err = rs.roleRepo.SaveRoleRight(roleRight)
+	if err != nil {
+		return nil, err
+	}
+
+	err = rs.changeRights(ctx, roleRight.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return roleRight, nil
+}
+
+// RevokeRoleRight denies every action set to 1 in revoke on its section
+// route and deletes the role right once it no longer allows anything.
+func (rs *roleService) RevokeRoleRight(ctx context.Context, revoke model.RoleRight) error {
+	roleRight, err := rs.roleRepo.GetRoleRight(int(revoke.RoleID), revoke.Section, revoke.Route)
+	if err != nil {
+		return err
+	}
+
+	roleRight.RCreate &^= revoke.RCreate
+	roleRight.RRead &^= revoke.RRead
+	roleRight.RUpdate &^= revoke.RUpdate
+	roleRight.RDelete &^= revoke.RDelete
+
+	if roleRight.RCreate == 0 && roleRight.RRead == 0 && roleRight.RUpdate == 0 && roleRight.RDelete == 0 {
+		err = rs.roleRepo.DeleteRoleRight(int(roleRight.ID))
+	} else {
+		err = rs.roleRepo.SaveRoleRight(roleRight)
+	}
+	if err != nil {
+		return err
+	}
+
+	return rs.changeRights(ctx, roleRight.RoleID)
+}
+
+// changeRights bumps the role's rights version, which marks tokens issued
+// with the old rights as stale, and drops its cached permissions.
+func (rs *roleService) changeRights(ctx context.Context, roleID uint) error {
+	err := rs.roleRepo.IncrementRightsVersion(roleID)
+	if err != nil {
+		return err
+	}
+
+	return rs.permissionService.InvalidateRole(ctx, roleID)
+}
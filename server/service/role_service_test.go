@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"tablelink_project/server/model"
+	"tablelink_project/server/repository"
+
+	"gorm.io/gorm"
+)
+
+// memoryRoleRights is an in-memory RoleRepository holding a single role and
+// its rights. err is returned by the role writes, standing in for database
+// constraint errors. Any other RoleRepository method panics through the nil
+// embedded interface.
+type memoryRoleRights struct {
+	repository.RoleRepository
+	role    *model.Role
+	rights  []*model.RoleRight
+	deleted []int
+	err     error
+}
+
+func (m *memoryRoleRights) CreateRole(role *model.Role) error {
+	if m.err != nil {
+		return m.err
+	}
+	role.ID = 1
+	m.role = role
+	return nil
+}
+
+func (m *memoryRoleRights) GetRoleByID(roleID int) (*model.Role, error) {
+	if m.role == nil || int(m.role.ID) != roleID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *m.role
+	return &copied, nil
+}
+
+func (m *memoryRoleRights) UpdateRole(role *model.Role) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.role.Name = role.Name
+	m.role.MFARequired = role.MFARequired
+	return nil
+}
+
+func (m *memoryRoleRights) DeleteRole(roleID int) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.role = nil
+	return nil
+}
+
+func (m *memoryRoleRights) IncrementRightsVersion(roleID uint) error {
+	m.role.RightsVersion++
+	return nil
+}
+
+func (m *memoryRoleRights) GetRoleRight(roleID int, section, route string) (*model.RoleRight, error) {
+	for _, roleRight := range m.rights {
+		if int(roleRight.RoleID) == roleID && roleRight.Section == section && roleRight.Route == route {
+			copied := *roleRight
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memoryRoleRights) SaveRoleRight(roleRight *model.RoleRight) error {
+	for i, existing := range m.rights {
+		if existing.ID == roleRight.ID && roleRight.ID != 0 {
+			m.rights[i] = roleRight
+			return nil
+		}
+	}
+	roleRight.ID = uint(len(m.rights) + 1)
+	m.rights = append(m.rights, roleRight)
+	return nil
+}
+
+func (m *memoryRoleRights) DeleteRoleRight(roleRightID int) error {
+	m.deleted = append(m.deleted, roleRightID)
+	return nil
+}
+
+// stubPermissions serves the permissions of roles, falling back to the same
+// permissions and rights version for every other role, and records the roles
+// whose cached permissions were invalidated. Any other PermissionService
+// method panics through the nil embedded interface.
+type stubPermissions struct {
+	PermissionService
+	permissions RolePermissions
+	roles       map[uint]RolePermissions
+	version     int
+	invalidated []uint
+}
+
+func (s *stubPermissions) GetRoleRights(ctx context.Context, roleID uint) (*RoleRights, error) {
+	permissions, _ := s.GetRolePermissions(ctx, roleID)
+	return &RoleRights{Name: "editor", Version: s.version, Permissions: permissions}, nil
+}
+
+func (s *stubPermissions) GetRolePermissions(ctx context.Context, roleID uint) (RolePermissions, error) {
+	if permissions, ok := s.roles[roleID]; ok {
+		return permissions, nil
+	}
+	return s.permissions, nil
+}
+
+func (s *stubPermissions) InvalidateRole(ctx context.Context, roleID uint) error {
+	s.invalidated = append(s.invalidated, roleID)
+	return nil
+}
+
+func TestRoleServiceGrantRoleRight(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *model.RoleRight
+		grant    model.RoleRight
+		want     model.RoleRight
+		wantErr  error
+	}{
+		{
+			name:  "new role right",
+			grant: model.RoleRight{RoleID: 1, Section: "users", Route: "/users", RRead: 1},
+			want:  model.RoleRight{ID: 1, RoleID: 1, Section: "users", Route: "/users", RRead: 1},
+		},
+		{
+			name:     "existing role right keeps its actions",
+			existing: &model.RoleRight{ID: 1, RoleID: 1, Section: "users", Route: "/users", RRead: 1},
+			grant:    model.RoleRight{RoleID: 1, Section: "users", Route: "/users", RCreate: 1, RDelete: 1},
+			want:     model.RoleRight{ID: 1, RoleID: 1, Section: "users", Route: "/users", RCreate: 1, RRead: 1, RDelete: 1},
+		},
+		{
+			name:    "unknown role",
+			grant:   model.RoleRight{RoleID: 2, Section: "users", Route: "/users", RRead: 1},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles := &memoryRoleRights{role: &model.Role{ID: 1, Name: "admin"}}
+			permissions := &stubPermissions{}
+			if tt.existing != nil {
+				roles.rights = append(roles.rights, tt.existing)
+			}
+
+			got, err := NewRoleService(roles, nil, permissions).GrantRoleRight(context.Background(), tt.grant)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GrantRoleRight() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(permissions.invalidated) != 1 || permissions.invalidated[0] != 1 {
+				t.Errorf("invalidated roles = %v, want [1]", permissions.invalidated)
+			}
+			if roles.role.RightsVersion != 1 {
+				t.Errorf("rights version = %d, want 1", roles.role.RightsVersion)
+			}
+			if *got != tt.want {
+				t.Errorf("GrantRoleRight() = %+v, want %+v", *got, tt.want)
+			}
+			if len(roles.rights) != 1 {
+				t.Errorf("stored %d role rights, want 1", len(roles.rights))
+			}
+		})
+	}
+}
+
+func TestRoleServiceRevokeRoleRight(t *testing.T) {
+	tests := []struct {
+		name        string
+		revoke      model.RoleRight
+		want        model.RoleRight
+		wantDeleted bool
+		wantErr     error
+	}{
+		{
+			name:   "some actions",
+			revoke: model.RoleRight{RoleID: 1, Section: "users", Route: "/users", RCreate: 1},
+			want:   model.RoleRight{ID: 1, RoleID: 1, Section: "users", Route: "/users", RRead: 1},
+		},
+		{
+			name:        "every action",
+			revoke:      model.RoleRight{RoleID: 1, Section: "users", Route: "/users", RCreate: 1, RRead: 1},
+			wantDeleted: true,
+		},
+		{
+			name:    "unknown route",
+			revoke:  model.RoleRight{RoleID: 1, Section: "users", Route: "/roles", RRead: 1},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles := &memoryRoleRights{
+				role:   &model.Role{ID: 1, Name: "admin"},
+				rights: []*model.RoleRight{{ID: 1, RoleID: 1, Section: "users", Route: "/users", RCreate: 1, RRead: 1}},
+			}
+			permissions := &stubPermissions{}
+
+			err := NewRoleService(roles, nil, permissions).RevokeRoleRight(context.Background(), tt.revoke)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RevokeRoleRight() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(permissions.invalidated) != 1 || permissions.invalidated[0] != 1 {
+				t.Errorf("invalidated roles = %v, want [1]", permissions.invalidated)
+			}
+			if roles.role.RightsVersion != 1 {
+				t.Errorf("rights version = %d, want 1", roles.role.RightsVersion)
+			}
+			if tt.wantDeleted {
+				if len(roles.deleted) != 1 || roles.deleted[0] != 1 {
+					t.Errorf("deleted role rights = %v, want [1]", roles.deleted)
+				}
+				return
+			}
+			if len(roles.deleted) != 0 {
+				t.Errorf("deleted role rights = %v, want none", roles.deleted)
+			}
+			if *roles.rights[0] != tt.want {
+				t.Errorf("role right = %+v, want %+v", *roles.rights[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestRoleServiceUpdateRole(t *testing.T) {
+	mfaRequired := true
+	tests := []struct {
+		name        string
+		update      model.Role
+		mfaRequired *bool
+		wantVersion int
+	}{
+		{"rename", model.Role{ID: 1, Name: "owner"}, nil, 1},
+		{"same name", model.Role{ID: 1, Name: "admin"}, &mfaRequired, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles := &memoryRoleRights{role: &model.Role{ID: 1, Name: "admin"}}
+			permissions := &stubPermissions{}
+
+			err := NewRoleService(roles, nil, permissions).UpdateRole(context.Background(), &tt.update, tt.mfaRequired)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if roles.role.RightsVersion != tt.wantVersion {
+				t.Errorf("rights version = %d, want %d", roles.role.RightsVersion, tt.wantVersion)
+			}
+			if len(permissions.invalidated) != tt.wantVersion {
+				t.Errorf("invalidated roles = %v, want %d", permissions.invalidated, tt.wantVersion)
+			}
+			if roles.role.MFARequired != (tt.mfaRequired != nil) {
+				t.Errorf("MFARequired = %v, want %v", roles.role.MFARequired, tt.mfaRequired != nil)
+			}
+		})
+	}
+}
+
+func TestRoleServiceCreateRoleNameTaken(t *testing.T) {
+	roles := &memoryRoleRights{err: gorm.ErrDuplicatedKey}
+	rs := NewRoleService(roles, nil, &stubPermissions{})
+
+	if err := rs.CreateRole(&model.Role{Name: "admin"}); !errors.Is(err, ErrRoleNameTaken) {
+		t.Errorf("CreateRole() error = %v, want %v", err, ErrRoleNameTaken)
+	}
+
+	roles = &memoryRoleRights{role: &model.Role{ID: 1, Name: "editor"}, err: gorm.ErrDuplicatedKey}
+	rs = NewRoleService(roles, nil, &stubPermissions{})
+	if err := rs.UpdateRole(context.Background(), &model.Role{ID: 1, Name: "admin"}, nil); !errors.Is(err, ErrRoleNameTaken) {
+		t.Errorf("UpdateRole() error = %v, want %v", err, ErrRoleNameTaken)
+	}
+}
+
+func TestRoleServiceDeleteRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		users   map[uint]*model.User
+		repoErr error
+		wantErr error
+		deleted bool
+	}{
+		{"unassigned role", map[uint]*model.User{1: {ID: 1, RoleID: 2}}, nil, nil, true},
+		{"assigned role", map[uint]*model.User{1: {ID: 1, RoleID: 1}}, nil, ErrRoleInUse, false},
+		{"assigned since counting", map[uint]*model.User{}, gorm.ErrForeignKeyViolated, ErrRoleInUse, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles := &memoryRoleRights{role: &model.Role{ID: 1, Name: "editor"}, err: tt.repoErr}
+			permissions := &stubPermissions{}
+
+			err := NewRoleService(roles, &memoryUsers{users: tt.users}, permissions).DeleteRole(context.Background(), 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeleteRole() error = %v, want %v", err, tt.wantErr)
+			}
+			if deleted := roles.role == nil; deleted != tt.deleted {
+				t.Errorf("role deleted = %v, want %v", deleted, tt.deleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"tablelink_project/server/model"
+	"tablelink_project/server/repository"
+	"tablelink_project/server/utils"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// API keys look like tlk_<12 hex characters>_<secret>. The part before
+	// the second underscore is the key's public prefix.
+	apiKeyScheme = "tlk_"
+	// apiKeyTouchInterval limits how often a key's last use is written back.
+	apiKeyTouchInterval       = time.Minute
+	maxAPIKeyScopes           = 32
+	serviceAccountEmailDomain = "service-accounts.tablelink.local"
+	// APIKeyScopeAll lets a key call every method its role allows.
+	APIKeyScopeAll = "*"
+)
+
+var (
+	ErrInvalidAPIKey          = errors.New("invalid api key")
+	ErrServiceAccountNotFound = errors.New("service account not found")
+	ErrAPIKeyNotFound         = errors.New("api key not found")
+
+	serviceAccountNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+)
+
+// APIKeyIdentity is the service account an API key authenticated.
+type APIKeyIdentity struct {
+	ServiceAccountID uint
+	KeyID            uint
+	Prefix           string
+	Scopes           []string
+}
+
+// Allows reports whether the key's scopes cover a full gRPC method name
+// such as /user.UserService/GetAllUsers.
+func (i *APIKeyIdentity) Allows(fullMethod string) bool {
+	method := strings.TrimPrefix(fullMethod, "/")
+	serviceName, _, _ := strings.Cut(method, "/")
+
+	for _, scope := range i.Scopes {
+		if scope == APIKeyScopeAll || scope == method || scope == serviceName {
+			return true
+		}
+	}
+	return false
+}
+
+type ServiceAccountService interface {
+	CreateServiceAccount(ctx context.Context, actorID uint, account model.User) (*model.User, error)
+	GetServiceAccounts() ([]model.User, error)
+	CreateAPIKey(serviceAccountID uint, name string, scopes []string) (*model.APIKey, string, error)
+	GetAPIKeys(serviceAccountID uint) ([]model.APIKey, error)
+	RevokeAPIKey(serviceAccountID, keyID uint) error
+	Authenticate(apiKey string) (*APIKeyIdentity, error)
+}
+
+type serviceAccountService struct {
+	userRepo          repository.UserRepository
+	roleRepo          repository.RoleRepository
+	apiKeyRepo        repository.APIKeyRepository
+	permissionService PermissionService
+}
+
+func NewServiceAccountService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, apiKeyRepo repository.APIKeyRepository, permissionService PermissionService) ServiceAccountService {
+	return &serviceAccountService{
+		userRepo:          userRepo,
+		roleRepo:          roleRepo,
+		apiKeyRepo:        apiKeyRepo,
+		permissionService: permissionService,
+	}
+}
+
+// CreateServiceAccount creates a user of kind service in the given role.
+// Service accounts have no password or email of their own and can only
+// authenticate with API keys. The role of actorID must cover the rights of
+// the account's role.
+func (ss *serviceAccountService) CreateServiceAccount(ctx context.Context, actorID uint, account model.User) (*model.User, error) {
+	account.Name = strings.TrimSpace(account.Name)
+	account.Email = account.Name + "@" + serviceAccountEmailDomain
+
+	validationErr := &ValidationError{}
+	if !serviceAccountNamePattern.MatchString(account.Name) {
+		validationErr.add("name", "must be 1 to 63 lowercase letters, digits or dashes")
+	} else if _, err := ss.userRepo.GetUserByEmail(account.Email); !errors.Is(err, gorm.ErrRecordNotFound) {
+		if err != nil {
+			return nil, err
+		}
+		validationErr.add("name", "is already in use")
+	}
+	if _, err := ss.roleRepo.GetRoleByID(int(account.RoleID)); err != nil {
+		validationErr.add("role_id", "must reference an existing role")
+	}
+	err := validationErr.orNil()
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkRoleCovered(ctx, ss.userRepo, ss.permissionService, actorID, account.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ss.userRepo.CreateUser(model.User{
+		Email:  account.Email,
+		Name:   account.Name,
+		RoleID: account.RoleID,
+		Status: model.UserStatusActive,
+		Kind:   model.UserKindService,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ss.userRepo.GetUserByEmail(account.Email)
+}
+
+func (ss *serviceAccountService) GetServiceAccounts() ([]model.User, error) {
+	return ss.userRepo.GetUsersByKind(model.UserKindService)
+}
+
+// CreateAPIKey creates a key for the service account and returns it along
+// with the key itself, which is not stored and cannot be shown again.
+func (ss *serviceAccountService) CreateAPIKey(serviceAccountID uint, name string, scopes []string) (*model.APIKey, string, error) {
+	_, err := ss.getServiceAccount(serviceAccountID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	name = strings.TrimSpace(name)
+	scopes = normalizeScopes(scopes)
+
+	validationErr := &ValidationError{}
+	if name == "" {
+		validationErr.add("name", "must not be empty")
+	} else if utf8.RuneCountInString(name) > maxNameLength {
+		validationErr.add("name", fmt.Sprintf("must be at most %d characters", maxNameLength))
+	}
+	if len(scopes) == 0 {
+		validationErr.add("scopes", "must not be empty")
+	} else if len(scopes) > maxAPIKeyScopes {
+		validationErr.add("scopes", fmt.Sprintf("must have at most %d entries", maxAPIKeyScopes))
+	}
+	for _, scope := range scopes {
+		if scope != APIKeyScopeAll && !utils.ScopeExists(scope) {
+			validationErr.add("scopes", fmt.Sprintf("%q is not a known service or method", scope))
+		}
+	}
+	err = validationErr.orNil()
+	if err != nil {
+		return nil, "", err
+	}
+
+	prefix := make([]byte, 6)
+	_, err = rand.Read(prefix)
+	if err != nil {
+		return nil, "", err
+	}
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return nil, "", err
+	}
+
+	apiKey := &model.APIKey{
+		ServiceAccountID: serviceAccountID,
+		Name:             name,
+		Prefix:           apiKeyScheme + hex.EncodeToString(prefix),
+		Scopes:           strings.Join(scopes, " "),
+	}
+	key := apiKey.Prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	apiKey.KeyHash = hashToken(key)
+
+	err = ss.apiKeyRepo.CreateAPIKey(apiKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return apiKey, key, nil
+}
+
+func (ss *serviceAccountService) GetAPIKeys(serviceAccountID uint) ([]model.APIKey, error) {
+	_, err := ss.getServiceAccount(serviceAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ss.apiKeyRepo.GetActiveAPIKeys(serviceAccountID)
+}
+
+func (ss *serviceAccountService) RevokeAPIKey(serviceAccountID, keyID uint) error {
+	revoked, err := ss.apiKeyRepo.RevokeAPIKey(serviceAccountID, keyID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// Authenticate resolves the service account of a live API key.
+func (ss *serviceAccountService) Authenticate(key string) (*APIKeyIdentity, error) {
+	prefix, _, ok := strings.Cut(strings.TrimPrefix(key, apiKeyScheme), "_")
+	if !ok || !strings.HasPrefix(key, apiKeyScheme) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := ss.apiKeyRepo.GetAPIKeyByPrefix(apiKeyScheme + prefix)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashToken(key)), []byte(apiKey.KeyHash)) != 1 || apiKey.RevokedAt != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyTouchInterval {
+		err = ss.apiKeyRepo.TouchAPIKey(apiKey.ID, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &APIKeyIdentity{
+		ServiceAccountID: apiKey.ServiceAccountID,
+		KeyID:            apiKey.ID,
+		Prefix:           apiKey.Prefix,
+		Scopes:           strings.Fields(apiKey.Scopes),
+	}, nil
+}
+
+func (ss *serviceAccountService) getServiceAccount(serviceAccountID uint) (*model.User, error) {
+	account, err := ss.userRepo.GetUserByID(int(serviceAccountID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrServiceAccountNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if account.Kind != model.UserKindService {
+		return nil, ErrServiceAccountNotFound
+	}
+
+	return account, nil
+}
+
+// normalizeScopes trims the scopes and drops empty and repeated ones.
+func normalizeScopes(scopes []string) []string {
+	normalized := make([]string, 0, len(scopes))
+	seen := map[string]bool{}
+	for _, scope := range scopes {
+		scope = strings.TrimPrefix(strings.TrimSpace(scope), "/")
+		if scope == "" || seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		normalized = append(normalized, scope)
+	}
+	return normalized
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"tablelink_project/server/model"
+
+	"gorm.io/gorm"
+)
+
+// memoryAPIKeys is an in-memory APIKeyRepository that counts last-use
+// writes.
+type memoryAPIKeys struct {
+	keys    []*model.APIKey
+	touches int
+}
+
+func (m *memoryAPIKeys) CreateAPIKey(apiKey *model.APIKey) error {
+	apiKey.ID = uint(len(m.keys) + 1)
+	stored := *apiKey
+	m.keys = append(m.keys, &stored)
+	return nil
+}
+
+func (m *memoryAPIKeys) GetAPIKeyByPrefix(prefix string) (*model.APIKey, error) {
+	for _, apiKey := range m.keys {
+		if apiKey.Prefix == prefix {
+			found := *apiKey
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memoryAPIKeys) GetActiveAPIKeys(serviceAccountID uint) ([]model.APIKey, error) {
+	apiKeys := []model.APIKey{}
+	for _, apiKey := range m.keys {
+		if apiKey.ServiceAccountID == serviceAccountID && apiKey.RevokedAt == nil {
+			apiKeys = append(apiKeys, *apiKey)
+		}
+	}
+	return apiKeys, nil
+}
+
+func (m *memoryAPIKeys) RevokeAPIKey(serviceAccountID, keyID uint) (bool, error) {
+	for _, apiKey := range m.keys {
+		if apiKey.ID == keyID && apiKey.ServiceAccountID == serviceAccountID && apiKey.RevokedAt == nil {
+			now := time.Now()
+			apiKey.RevokedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryAPIKeys) TouchAPIKey(keyID uint, usedAt time.Time) error {
+	m.touches++
+	m.keys[keyID-1].LastUsedAt = &usedAt
+	return nil
+}
+
+func newTestServiceAccountService() (ServiceAccountService, *memoryAPIKeys) {
+	users := &memoryUsers{users: map[uint]*model.User{
+		1: {ID: 1, Name: "billing", RoleID: 1, Status: model.UserStatusActive, Kind: model.UserKindService},
+		2: {ID: 2, Name: "jane", RoleID: 1, Status: model.UserStatusActive, Kind: model.UserKindHuman},
+	}}
+	apiKeys := &memoryAPIKeys{}
+	permissions := &stubPermissions{roles: map[uint]RolePermissions{
+		1: {"users /users": rightRead},
+		2: {"users /users": rightRead | rightDelete},
+	}}
+	return NewServiceAccountService(users, &memoryRoles{ids: map[uint]bool{1: true, 2: true}}, apiKeys, permissions), apiKeys
+}
+
+func TestServiceAccountServiceAuthenticate(t *testing.T) {
+	service, apiKeys := newTestServiceAccountService()
+
+	apiKey, key, err := service.CreateAPIKey(1, "ci", []string{"user.UserService/GetAllUsers", " /role.RoleService ", "user.UserService/GetAllUsers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(key, apiKey.Prefix+"_") || apiKey.KeyHash == "" || strings.Contains(apiKey.KeyHash, key) {
+		t.Fatalf("CreateAPIKey() = %+v, %q, want a hashed key behind its prefix", apiKey, key)
+	}
+	if apiKey.Scopes != "user.UserService/GetAllUsers role.RoleService" {
+		t.Errorf("scopes = %q, want normalized scopes", apiKey.Scopes)
+	}
+
+	_, otherKey, err := service.CreateAPIKey(1, "other", []string{APIKeyScopeAll})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, secret, _ := strings.Cut(strings.TrimPrefix(otherKey, apiKeyScheme), "_")
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"missing scheme", strings.TrimPrefix(key, apiKeyScheme)},
+		{"other scheme", "sk_" + strings.TrimPrefix(key, apiKeyScheme)},
+		{"missing secret", apiKey.Prefix},
+		{"unknown prefix", apiKeyScheme + "000000000000_" + secret},
+		{"secret of another key", apiKey.Prefix + "_" + secret},
+		{"truncated secret", key[:len(key)-1]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.Authenticate(tt.key); !errors.Is(err, ErrInvalidAPIKey) {
+				t.Errorf("Authenticate() error = %v, want ErrInvalidAPIKey", err)
+			}
+		})
+	}
+
+	identity, err := service.Authenticate(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.ServiceAccountID != 1 || identity.KeyID != apiKey.ID || identity.Prefix != apiKey.Prefix {
+		t.Errorf("Authenticate() = %+v, want key %d of service account 1", identity, apiKey.ID)
+	}
+	if len(identity.Scopes) != 2 || identity.Scopes[1] != "role.RoleService" {
+		t.Errorf("scopes = %v, want the key's scopes", identity.Scopes)
+	}
+
+	// Last use is written at most once per interval.
+	if _, err := service.Authenticate(key); err != nil {
+		t.Fatal(err)
+	}
+	if apiKeys.touches != 1 {
+		t.Errorf("last use written %d times, want 1", apiKeys.touches)
+	}
+
+	if err := service.RevokeAPIKey(1, apiKey.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Authenticate(key); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Authenticate(revoked key) error = %v, want ErrInvalidAPIKey", err)
+	}
+	if err := service.RevokeAPIKey(1, apiKey.ID); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("RevokeAPIKey(revoked key) error = %v, want ErrAPIKeyNotFound", err)
+	}
+}
+
+func TestServiceAccountServiceCreateServiceAccount(t *testing.T) {
+	tests := []struct {
+		name    string
+		account model.User
+		wantErr error
+		invalid bool
+	}{
+		{"role of the caller", model.User{Name: "reporting", RoleID: 1}, nil, false},
+		{"broader role", model.User{Name: "reporting", RoleID: 2}, ErrRoleNotCovered, false},
+		{"invalid name", model.User{Name: "Reporting!", RoleID: 1}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestServiceAccountService()
+
+			// User 2 is a human in role 1.
+			account, err := service.CreateServiceAccount(context.Background(), 2, tt.account)
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) != tt.invalid {
+				t.Fatalf("CreateServiceAccount() error = %v, want ValidationError %v", err, tt.invalid)
+			}
+			if !tt.invalid && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateServiceAccount() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (account.Kind != model.UserKindService || account.RoleID != tt.account.RoleID) {
+				t.Errorf("CreateServiceAccount() = %+v, want a service account in role %d", account, tt.account.RoleID)
+			}
+		})
+	}
+}
+
+func TestServiceAccountServiceCreateAPIKeyValidation(t *testing.T) {
+	service, _ := newTestServiceAccountService()
+
+	tests := []struct {
+		name             string
+		serviceAccountID uint
+		keyName          string
+		scopes           []string
+		wantField        string
+		wantErr          error
+	}{
+		{"human user", 2, "ci", []string{APIKeyScopeAll}, "", ErrServiceAccountNotFound},
+		{"unknown account", 3, "ci", []string{APIKeyScopeAll}, "", ErrServiceAccountNotFound},
+		{"empty name", 1, " ", []string{APIKeyScopeAll}, "name", nil},
+		{"no scopes", 1, "ci", []string{" ", ""}, "scopes", nil},
+		{"unknown scope", 1, "ci", []string{"user.UserService/Missing"}, "scopes", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := service.CreateAPIKey(tt.serviceAccountID, tt.keyName, tt.scopes)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("CreateAPIKey() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || len(validationErr.Violations) != 1 || validationErr.Violations[0].Field != tt.wantField {
+				t.Errorf("CreateAPIKey() error = %v, want a violation of %s", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestAPIKeyIdentityAllows(t *testing.T) {
+	tests := []struct {
+		name       string
+		scopes     []string
+		fullMethod string
+		want       bool
+	}{
+		{"all methods", []string{APIKeyScopeAll}, "/role.RoleService/DeleteRole", true},
+		{"method scope", []string{"user.UserService/GetAllUsers"}, "/user.UserService/GetAllUsers", true},
+		{"other method of the service", []string{"user.UserService/GetAllUsers"}, "/user.UserService/DeleteUser", false},
+		{"service scope", []string{"user.UserService"}, "/user.UserService/DeleteUser", true},
+		{"other service", []string{"user.UserService"}, "/role.RoleService/GetAllRoles", false},
+		{"service name prefix", []string{"user.User"}, "/user.UserService/GetAllUsers", false},
+		{"no scopes", nil, "/user.UserService/GetAllUsers", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity := &APIKeyIdentity{Scopes: tt.scopes}
+			if got := identity.Allows(tt.fullMethod); got != tt.want {
+				t.Errorf("Allows(%s) = %v, want %v", tt.fullMethod, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,479 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"tablelink_project/server/model"
+	"tablelink_project/server/repository"
+	"tablelink_project/server/utils"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, token family revoked")
+	ErrSessionNotFound     = errors.New("session not found")
+)
+
+// sessionTouchInterval limits how often a session's last-seen time is
+// written back, so authenticated calls do not all write to Redis.
+const sessionTouchInterval = time.Minute
+
+// SessionClient describes the client a session was started or used from.
+type SessionClient struct {
+	Device    string
+	UserAgent string
+	IP        string
+}
+
+type TokenService interface {
+	IssueToken(ctx context.Context, userID uint, client SessionClient) (*model.Token, error)
+	RotateToken(ctx context.Context, claim *utils.RefreshTokenClaim) (*model.Token, error)
+	GetActiveTokens(userID int) ([]model.Token, error)
+	GetSessions(ctx context.Context, userID uint) ([]model.Session, error)
+	RevokeToken(ctx context.Context, claim *utils.UserClaim) error
+	TouchSession(ctx context.Context, sessionID, ip string) error
+	RevokeSession(ctx context.Context, userID uint, sessionID string) error
+	RevokeOtherSessions(ctx context.Context, userID uint, keepSessionID string) error
+	RevokeUserTokens(ctx context.Context, userID int) error
+	ExpireAccessTokens(ctx context.Context, userID uint) error
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+	IsRefreshTokenActive(ctx context.Context, claim *utils.RefreshTokenClaim) (bool, error)
+	IssueMFAChallenge(userID uint) (string, time.Time, error)
+	IssueImpersonationToken(ctx context.Context, actorID, userID uint, sessionID string) (string, time.Time, error)
+	ConsumeMFAChallenge(ctx context.Context, claim *utils.MFAChallengeClaim) error
+}
+
+type tokenService struct {
+	tokenRepo         repository.TokenRepository
+	denylistRepo      repository.TokenDenylistRepository
+	sessionRepo       repository.SessionRepository
+	userRepo          repository.UserRepository
+	permissionService PermissionService
+	tokenGenerator    utils.CredentialTokenGenerator
+	embedRoleClaims   bool
+}
+
+// NewTokenService creates a TokenService. With embedRoleClaims set, access
+// tokens carry the user's role and a digest of its rights.
+func NewTokenService(
+	tokenRepo repository.TokenRepository,
+	denylistRepo repository.TokenDenylistRepository,
+	sessionRepo repository.SessionRepository,
+	userRepo repository.UserRepository,
+	permissionService PermissionService,
+	tokenGenerator utils.CredentialTokenGenerator,
+	embedRoleClaims bool,
+) TokenService {
+	return &tokenService{
+		tokenRepo:         tokenRepo,
+		denylistRepo:      denylistRepo,
+		sessionRepo:       sessionRepo,
+		userRepo:          userRepo,
+		permissionService: permissionService,
+		tokenGenerator:    tokenGenerator,
+		embedRoleClaims:   embedRoleClaims,
+	}
+}
+
+// IssueToken starts a new session with its own token family for the user
+// and records its first access/refresh pair in the tokens table.
+func (ts *tokenService) IssueToken(ctx context.Context, userID uint, client SessionClient) (*model.Token, error) {
+	now := time.Now()
+	session := &model.Session{
+		ID:         uuid.NewString(),
+		UserID:     userID,
+		Device:     client.Device,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		FamilyID:   uuid.NewString(),
+		IssuedAt:   now,
+		LastSeenAt: now,
+	}
+
+	token, err := ts.issue(ctx, userID, session.ID, session.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	session.ExpiredAt = token.RefreshExpiredAt
+	err = ts.sessionRepo.Save(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// RotateToken exchanges a refresh token for a new pair in the same family.
+// Refresh tokens are single use: presenting one that was already exchanged
+// means it leaked, so the whole family is revoked and the user must log in
+// again. Refresh tokens of a session that ended are rejected.
+func (ts *tokenService) RotateToken(ctx context.Context, claim *utils.RefreshTokenClaim) (*model.Token, error) {
+	token, err := ts.tokenRepo.GetTokenByRefreshTokenID(claim.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if token.UsedAt != nil {
+		return nil, ts.revokeReusedFamily(ctx, token)
+	}
+	if token.RevokedAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	revoked, err := ts.IsTokenRevoked(ctx, token.RefreshTokenID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	session, err := ts.sessionRepo.Get(ctx, token.SessionID)
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	marked, err := ts.tokenRepo.MarkRefreshTokenUsed(token.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		return nil, ts.revokeReusedFamily(ctx, token)
+	}
+
+	newToken, err := ts.issue(ctx, token.UserID, session.ID, session.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	session.ExpiredAt = newToken.RefreshExpiredAt
+	session.LastSeenAt = time.Now()
+	err = ts.sessionRepo.Save(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return newToken, nil
+}
+
+func (ts *tokenService) GetActiveTokens(userID int) ([]model.Token, error) {
+	return ts.tokenRepo.GetActiveTokensByUserID(userID)
+}
+
+func (ts *tokenService) GetSessions(ctx context.Context, userID uint) ([]model.Session, error) {
+	return ts.sessionRepo.GetByUserID(ctx, userID)
+}
+
+// RevokeToken ends the session the access token described by claim belongs
+// to. Tokens issued before sessions existed only have their own pair
+// revoked, and tokens without a tokens row have no usable refresh token, so
+// only the access token is denylisted for them.
+func (ts *tokenService) RevokeToken(ctx context.Context, claim *utils.UserClaim) error {
+	if claim.Id == "" {
+		return errors.New("token has no id")
+	}
+
+	token, err := ts.tokenRepo.GetTokenByAccessTokenID(claim.Id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if token != nil && token.SessionID != "" {
+		err = ts.RevokeSession(ctx, token.UserID, token.SessionID)
+		if !errors.Is(err, ErrSessionNotFound) {
+			return err
+		}
+	}
+	if token != nil {
+		return ts.revoke(ctx, token)
+	}
+
+	return ts.denylistRepo.Add(ctx, claim.Id, time.Until(time.Unix(claim.ExpiresAt, 0)))
+}
+
+// TouchSession records that the session was just used from ip. It returns
+// ErrSessionNotFound once the session was revoked or expired.
+func (ts *tokenService) TouchSession(ctx context.Context, sessionID, ip string) error {
+	session, err := ts.sessionRepo.Get(ctx, sessionID)
+	if errors.Is(err, redis.Nil) {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if time.Since(session.LastSeenAt) < sessionTouchInterval && (ip == "" || ip == session.IP) {
+		return nil
+	}
+
+	session.LastSeenAt = time.Now()
+	if ip != "" {
+		session.IP = ip
+	}
+	return ts.sessionRepo.Save(ctx, session)
+}
+
+// RevokeSession revokes every live token pair of the user's session and
+// deletes the session. Sessions of other users are reported as not found.
+func (ts *tokenService) RevokeSession(ctx context.Context, userID uint, sessionID string) error {
+	session, err := ts.sessionRepo.Get(ctx, sessionID)
+	if errors.Is(err, redis.Nil) {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID {
+		return ErrSessionNotFound
+	}
+
+	return ts.endSession(ctx, session)
+}
+
+// RevokeOtherSessions ends every session of the user except keepSessionID.
+func (ts *tokenService) RevokeOtherSessions(ctx context.Context, userID uint, keepSessionID string) error {
+	sessions, err := ts.sessionRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for i := range sessions {
+		if sessions[i].ID == keepSessionID {
+			continue
+		}
+		err = ts.endSession(ctx, &sessions[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RevokeUserTokens revokes every live token pair issued to the user and
+// deletes all of their sessions.
+func (ts *tokenService) RevokeUserTokens(ctx context.Context, userID int) error {
+	tokens, err := ts.tokenRepo.GetActiveTokensByUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	for i := range tokens {
+		err = ts.revoke(ctx, &tokens[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	sessions, err := ts.sessionRepo.GetByUserID(ctx, uint(userID))
+	if err != nil {
+		return err
+	}
+
+	for i := range sessions {
+		err = ts.sessionRepo.Delete(ctx, &sessions[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExpireAccessTokens revokes the user's live access tokens but keeps their
+// sessions and refresh tokens, so clients refresh and get tokens with the
+// user's current role. It does nothing unless role claims are embedded.
+func (ts *tokenService) ExpireAccessTokens(ctx context.Context, userID uint) error {
+	if !ts.embedRoleClaims {
+		return nil
+	}
+
+	tokens, err := ts.tokenRepo.GetActiveTokensByUserID(int(userID))
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		err = ts.denylistRepo.Add(ctx, token.AccessTokenID, time.Until(token.ExpiredAt))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ts *tokenService) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	if tokenID == "" {
+		return false, nil
+	}
+	return ts.denylistRepo.Exists(ctx, tokenID)
+}
+
+// IsSessionActive reports whether the session was neither revoked nor
+// expired, without recording a use of it.
+func (ts *tokenService) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	_, err := ts.sessionRepo.Get(ctx, sessionID)
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// IsRefreshTokenActive reports whether RotateToken would accept the refresh
+// token: it was neither exchanged nor revoked and its session is live.
+func (ts *tokenService) IsRefreshTokenActive(ctx context.Context, claim *utils.RefreshTokenClaim) (bool, error) {
+	token, err := ts.tokenRepo.GetTokenByRefreshTokenID(claim.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if token.UsedAt != nil || token.RevokedAt != nil {
+		return false, nil
+	}
+
+	revoked, err := ts.IsTokenRevoked(ctx, token.RefreshTokenID)
+	if err != nil || revoked {
+		return false, err
+	}
+
+	return ts.IsSessionActive(ctx, token.SessionID)
+}
+
+func (ts *tokenService) IssueMFAChallenge(userID uint) (string, time.Time, error) {
+	return ts.tokenGenerator.GenerateMFAChallenge(userID)
+}
+
+// IssueImpersonationToken issues an access token for userID on behalf of
+// actorID within the actor's session. No tokens row is recorded: the token
+// cannot be refreshed and dies with the session or after a few minutes.
+func (ts *tokenService) IssueImpersonationToken(ctx context.Context, actorID, userID uint, sessionID string) (string, time.Time, error) {
+	role, err := ts.roleClaims(ctx, userID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return ts.tokenGenerator.GenerateImpersonation(userID, role, actorID, sessionID)
+}
+
+// ConsumeMFAChallenge denylists a challenge once it was exchanged, so it
+// cannot be used for a second login.
+func (ts *tokenService) ConsumeMFAChallenge(ctx context.Context, claim *utils.MFAChallengeClaim) error {
+	return ts.denylistRepo.Add(ctx, claim.Id, time.Until(time.Unix(claim.ExpiresAt, 0)))
+}
+
+func (ts *tokenService) issue(ctx context.Context, userID uint, sessionID, familyID string) (*model.Token, error) {
+	role, err := ts.roleClaims(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := ts.tokenGenerator.Generate(userID, role, sessionID, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ts.tokenRepo.CreateToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// roleClaims describes the user's current role for their next access token,
+// or returns nil when role claims are not embedded.
+func (ts *tokenService) roleClaims(ctx context.Context, userID uint) (*utils.RoleClaims, error) {
+	if !ts.embedRoleClaims {
+		return nil, nil
+	}
+
+	roleID, err := ts.userRepo.GetUserRoleID(int(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	rights, err := ts.permissionService.GetRoleRights(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &utils.RoleClaims{
+		RoleID:        roleID,
+		RoleName:      rights.Name,
+		RightsVersion: rights.Version,
+		Permissions:   rights.Permissions.Digest(),
+	}, nil
+}
+
+// revokeReusedFamily ends the session of a reused refresh token and always
+// returns ErrRefreshTokenReused unless revocation itself failed.
+func (ts *tokenService) revokeReusedFamily(ctx context.Context, token *model.Token) error {
+	err := ts.RevokeSession(ctx, token.UserID, token.SessionID)
+	if errors.Is(err, ErrSessionNotFound) {
+		err = ts.revokeFamily(ctx, token.FamilyID)
+	}
+	if err != nil {
+		return err
+	}
+
+	return ErrRefreshTokenReused
+}
+
+func (ts *tokenService) endSession(ctx context.Context, session *model.Session) error {
+	err := ts.revokeFamily(ctx, session.FamilyID)
+	if err != nil {
+		return err
+	}
+
+	return ts.sessionRepo.Delete(ctx, session)
+}
+
+// revokeFamily revokes every live pair of a token family.
+func (ts *tokenService) revokeFamily(ctx context.Context, familyID string) error {
+	tokens, err := ts.tokenRepo.GetActiveTokensByFamilyID(familyID)
+	if err != nil {
+		return err
+	}
+
+	for i := range tokens {
+		err = ts.revoke(ctx, &tokens[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ts *tokenService) revoke(ctx context.Context, token *model.Token) error {
+	err := ts.denylistRepo.Add(ctx, token.AccessTokenID, time.Until(token.ExpiredAt))
+	if err != nil {
+		return err
+	}
+
+	err = ts.denylistRepo.Add(ctx, token.RefreshTokenID, time.Until(token.RefreshExpiredAt))
+	if err != nil {
+		return err
+	}
+
+	return ts.tokenRepo.RevokeToken(token.ID)
+}
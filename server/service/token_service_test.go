@@ -0,0 +1,689 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tablelink_project/server/model"
+	"tablelink_project/server/utils"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang-jwt/jwt"
+	"gorm.io/gorm"
+)
+
+func testTokenGenerator() utils.CredentialTokenGenerator {
+	key, err := utils.NewHMACKey("test", jwt.SigningMethodHS256, "test-secret")
+	if err != nil {
+		panic(err)
+	}
+	ring, err := utils.NewKeyring([]*utils.SigningKey{key}, key.ID)
+	if err != nil {
+		panic(err)
+	}
+	return utils.NewJWT(utils.JWTConfig{Keyring: ring})
+}
+
+// memoryDenylist is an in-memory TokenDenylistRepository that records the
+// TTL every token id was denylisted with.
+type memoryDenylist struct {
+	ttls map[string]time.Duration
+}
+
+func newMemoryDenylist() *memoryDenylist {
+	return &memoryDenylist{ttls: map[string]time.Duration{}}
+}
+
+func (m *memoryDenylist) Add(ctx context.Context, tokenID string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	m.ttls[tokenID] = ttl
+	return nil
+}
+
+func (m *memoryDenylist) Exists(ctx context.Context, tokenID string) (bool, error) {
+	_, ok := m.ttls[tokenID]
+	return ok, nil
+}
+
+// memorySessions is an in-memory SessionRepository that counts writes.
+type memorySessions struct {
+	sessions map[string]model.Session
+	saves    int
+}
+
+func newMemorySessions() *memorySessions {
+	return &memorySessions{sessions: map[string]model.Session{}}
+}
+
+func (m *memorySessions) Save(ctx context.Context, session *model.Session) error {
+	m.saves++
+	m.sessions[session.ID] = *session
+	return nil
+}
+
+func (m *memorySessions) Get(ctx context.Context, sessionID string) (*model.Session, error) {
+	session, ok := m.sessions[sessionID]
+	if !ok {
+		return nil, redis.Nil
+	}
+	return &session, nil
+}
+
+func (m *memorySessions) GetByUserID(ctx context.Context, userID uint) ([]model.Session, error) {
+	sessions := []model.Session{}
+	for _, session := range m.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (m *memorySessions) Delete(ctx context.Context, session *model.Session) error {
+	delete(m.sessions, session.ID)
+	return nil
+}
+
+// addSession records a live session of the user.
+func (m *memorySessions) addSession(userID uint, sessionID, familyID string) {
+	m.sessions[sessionID] = model.Session{
+		ID:         sessionID,
+		UserID:     userID,
+		FamilyID:   familyID,
+		IssuedAt:   time.Now(),
+		LastSeenAt: time.Now(),
+		ExpiredAt:  time.Now().Add(24 * time.Hour),
+	}
+}
+
+// memoryTokens is an in-memory TokenRepository. beforeMark, when set, runs
+// before a refresh token is marked used, so a test can exchange the token
+// concurrently.
+type memoryTokens struct {
+	tokens     []*model.Token
+	beforeMark func(tokenID uint)
+}
+
+func (m *memoryTokens) CreateToken(token *model.Token) error {
+	token.ID = uint(len(m.tokens) + 1)
+	token.CreatedAt = time.Now()
+	stored := *token
+	m.tokens = append(m.tokens, &stored)
+	return nil
+}
+
+func (m *memoryTokens) GetTokenByAccessTokenID(tokenID string) (*model.Token, error) {
+	for _, token := range m.tokens {
+		if token.AccessTokenID == tokenID {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memoryTokens) GetTokenByRefreshTokenID(tokenID string) (*model.Token, error) {
+	for _, token := range m.tokens {
+		if token.RefreshTokenID == tokenID {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memoryTokens) GetActiveTokensByUserID(userID int) ([]model.Token, error) {
+	var tokens []model.Token
+	for _, token := range m.tokens {
+		if token.UserID == uint(userID) && token.RevokedAt == nil && token.RefreshExpiredAt.After(time.Now()) {
+			tokens = append(tokens, *token)
+		}
+	}
+	return tokens, nil
+}
+
+func (m *memoryTokens) GetActiveTokensByFamilyID(familyID string) ([]model.Token, error) {
+	var tokens []model.Token
+	for _, token := range m.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			tokens = append(tokens, *token)
+		}
+	}
+	return tokens, nil
+}
+
+func (m *memoryTokens) MarkRefreshTokenUsed(tokenID uint) (bool, error) {
+	if m.beforeMark != nil {
+		m.beforeMark(tokenID)
+	}
+	for _, token := range m.tokens {
+		if token.ID == tokenID && token.UsedAt == nil {
+			now := time.Now()
+			token.UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryTokens) RevokeToken(tokenID uint) error {
+	for _, token := range m.tokens {
+		if token.ID == tokenID && token.RevokedAt == nil {
+			now := time.Now()
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// addToken records a live pair of the user with the given token ids.
+func (m *memoryTokens) addToken(userID uint, sessionID, familyID, accessTokenID, refreshTokenID string) *model.Token {
+	token := &model.Token{
+		UserID:           userID,
+		SessionID:        sessionID,
+		FamilyID:         familyID,
+		AccessTokenID:    accessTokenID,
+		RefreshTokenID:   refreshTokenID,
+		ExpiredAt:        time.Now().Add(time.Hour),
+		RefreshExpiredAt: time.Now().Add(24 * time.Hour),
+	}
+	m.CreateToken(token)
+	return m.tokens[len(m.tokens)-1]
+}
+
+func TestTokenServiceRevokeToken(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		claim        *utils.UserClaim
+		sessionEnded bool
+		revoked      []string
+		revokesRow   bool
+		wantErr      bool
+	}{
+		{
+			name: "token without a tokens row",
+			claim: &utils.UserClaim{
+				RefreshTokenID: "refresh-1",
+				StandardClaims: jwt.StandardClaims{Id: "access-1", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix()},
+			},
+			revoked: []string{"access-1"},
+		},
+		{
+			name: "expired token without a tokens row",
+			claim: &utils.UserClaim{
+				RefreshTokenID: "refresh-1",
+				StandardClaims: jwt.StandardClaims{Id: "access-1", IssuedAt: now.Add(-5 * time.Hour).Unix(), ExpiresAt: now.Add(-time.Hour).Unix()},
+			},
+		},
+		{
+			name: "pair of a live session",
+			claim: &utils.UserClaim{
+				SessionID:      "session-1",
+				StandardClaims: jwt.StandardClaims{Id: "access-2", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix()},
+			},
+			revoked:    []string{"access-2", "refresh-2", "access-3", "refresh-3"},
+			revokesRow: true,
+		},
+		{
+			name: "pair of an ended session",
+			claim: &utils.UserClaim{
+				SessionID:      "session-1",
+				StandardClaims: jwt.StandardClaims{Id: "access-2", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix()},
+			},
+			sessionEnded: true,
+			revoked:      []string{"access-2", "refresh-2"},
+			revokesRow:   true,
+		},
+		{
+			name:    "token without id",
+			claim:   &utils.UserClaim{StandardClaims: jwt.StandardClaims{ExpiresAt: now.Add(time.Hour).Unix()}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tokens := &memoryTokens{}
+			recorded := tokens.addToken(1, "session-1", "family-1", "access-2", "refresh-2")
+			tokens.addToken(1, "session-1", "family-1", "access-3", "refresh-3")
+			denylist := newMemoryDenylist()
+			sessions := newMemorySessions()
+			if !tt.sessionEnded {
+				sessions.addSession(1, "session-1", "family-1")
+			}
+			ts := NewTokenService(tokens, denylist, sessions, nil, nil, testTokenGenerator(), false)
+
+			err := ts.RevokeToken(ctx, tt.claim)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RevokeToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(denylist.ttls) != len(tt.revoked) {
+				t.Errorf("denylisted %v, want %v", denylist.ttls, tt.revoked)
+			}
+			for _, tokenID := range tt.revoked {
+				if revoked, _ := ts.IsTokenRevoked(ctx, tokenID); !revoked {
+					t.Errorf("IsTokenRevoked(%s) = false, want true", tokenID)
+				}
+			}
+			if (recorded.RevokedAt != nil) != tt.revokesRow {
+				t.Errorf("tokens row revoked = %v, want %v", recorded.RevokedAt != nil, tt.revokesRow)
+			}
+			if tt.revokesRow && len(sessions.sessions) != 0 {
+				t.Errorf("sessions = %v, want the session ended", sessions.sessions)
+			}
+		})
+	}
+}
+
+func TestTokenServiceIsTokenRevokedWithoutID(t *testing.T) {
+	denylist := newMemoryDenylist()
+	denylist.ttls[""] = time.Hour
+
+	revoked, err := NewTokenService(&memoryTokens{}, denylist, newMemorySessions(), nil, nil, testTokenGenerator(), false).IsTokenRevoked(context.Background(), "")
+	if err != nil || revoked {
+		t.Errorf("IsTokenRevoked(\"\") = %v, %v, want false, nil", revoked, err)
+	}
+}
+
+func TestTokenServiceRevokeUserTokens(t *testing.T) {
+	ctx := context.Background()
+	tokens := &memoryTokens{}
+	first := tokens.addToken(1, "session-1", "family-1", "access-1", "refresh-1")
+	second := tokens.addToken(1, "session-2", "family-2", "access-2", "refresh-2")
+	other := tokens.addToken(2, "session-3", "family-3", "access-3", "refresh-3")
+	denylist := newMemoryDenylist()
+	sessions := newMemorySessions()
+	sessions.addSession(1, "session-1", "family-1")
+	sessions.addSession(1, "session-2", "family-2")
+	sessions.addSession(2, "session-3", "family-3")
+	ts := NewTokenService(tokens, denylist, sessions, nil, nil, testTokenGenerator(), false)
+
+	if err := ts.RevokeUserTokens(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range []*model.Token{first, second} {
+		if token.RevokedAt == nil {
+			t.Errorf("token %d of the user was not revoked", token.ID)
+		}
+	}
+	if other.RevokedAt != nil {
+		t.Error("token of another user was revoked")
+	}
+	for tokenID, want := range map[string]bool{"access-1": true, "refresh-2": true, "access-3": false} {
+		if revoked, _ := ts.IsTokenRevoked(ctx, tokenID); revoked != want {
+			t.Errorf("IsTokenRevoked(%s) = %v, want %v", tokenID, revoked, want)
+		}
+	}
+	if active, _ := ts.GetActiveTokens(1); len(active) != 0 {
+		t.Errorf("GetActiveTokens() = %d tokens, want none", len(active))
+	}
+	if _, ok := sessions.sessions["session-3"]; len(sessions.sessions) != 1 || !ok {
+		t.Errorf("sessions = %v, want only the session of another user", sessions.sessions)
+	}
+}
+
+func TestTokenServiceRotateToken(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup records the family of the presented refresh token
+		// "refresh-1", whose session-1 is live, and returns the ids
+		// expected to be revoked.
+		setup   func(tokens *memoryTokens, denylist *memoryDenylist, sessions *memorySessions) []string
+		refresh string
+		wantErr error
+	}{
+		{
+			name: "rotation",
+			setup: func(tokens *memoryTokens, denylist *memoryDenylist, sessions *memorySessions) []string {
+				tokens.addToken(1, "session-1", "family-1", "access-1", "refresh-1")
+				return nil
+			},
+			refresh: "refresh-1",
+		},
+		{
+			name: "unknown refresh token",
+			setup: func(tokens *memoryTokens, denylist *memoryDenylist, sessions *memorySessions) []string {
+				return nil
+			},
+			refresh: "refresh-1",
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "revoked refresh token",
+			setup: func(tokens *memoryTokens, denylist *memoryDenylist, sessions *memorySessions) []string {
+				tokens.RevokeToken(tokens.addToken(1, "session-1", "family-1", "access-1", "refresh-1").ID)
+				return nil
+			},
+			refresh: "refresh-1",
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "denylisted refresh token",
+			setup: func(tokens *memoryTokens, denylist *memoryDenylist, sessions *memorySessions) []string {
+				tokens.addToken(1, "session-1", "family-1", "access-1", "refresh-1")
+				denylist.Add(context.Background(), "refresh-1", time.Hour)
+				return nil
+			},
+			refresh: "refresh-1",
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "ended session",
+			setup: func(tokens *memoryTokens, denylist *memoryDenylist, sessions *memorySessions) []string {
+				tokens.addToken(1, "session-1", "family-1", "access-1", "refresh-1")
+				delete(sessions.sessions, "session-1")
+				return nil
+			},
+			refresh: "refresh-1",
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "reuse of a rotated refresh token",
+			setup: func(tokens *memoryTokens, denylist *memoryDenylist, sessions *memorySessions) []string {
+				rotated := tokens.addToken(1, "session-1", "family-1", "access-1", "refresh-1")
+				tokens.MarkRefreshTokenUsed(rotated.ID)
+				tokens.addToken(1, "session-1", "family-1", "access-2", "refresh-2")
+				return []string{"access-2", "refresh-2"}
+			},
+			refresh: "refresh-1",
+			wantErr: ErrRefreshTokenReused,
+		},
+		{
+			name: "concurrent double use",
+			setup: func(tokens *memoryTokens, denylist *memoryDenylist, sessions *memorySessions) []string {
+				tokens.addToken(1, "session-1", "family-1", "access-1", "refresh-1")
+				tokens.beforeMark = func(tokenID uint) {
+					// Another request exchanges the token first.
+					tokens.beforeMark = nil
+					tokens.MarkRefreshTokenUsed(tokenID)
+					tokens.addToken(1, "session-1", "family-1", "access-2", "refresh-2")
+				}
+				return []string{"access-1", "refresh-1", "access-2", "refresh-2"}
+			},
+			refresh: "refresh-1",
+			wantErr: ErrRefreshTokenReused,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tokens := &memoryTokens{}
+			denylist := newMemoryDenylist()
+			sessions := newMemorySessions()
+			sessions.addSession(1, "session-1", "family-1")
+			sessions.addSession(1, "session-2", "family-2")
+			revoked := tt.setup(tokens, denylist, sessions)
+			other := tokens.addToken(1, "session-2", "family-2", "access-other", "refresh-other")
+			ts := NewTokenService(tokens, denylist, sessions, nil, nil, testTokenGenerator(), false)
+
+			claim := &utils.RefreshTokenClaim{UserID: 1, RefreshToken: true}
+			claim.Id = tt.refresh
+			token, err := ts.RotateToken(ctx, claim)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RotateToken() error = %v, want %v", err, tt.wantErr)
+			}
+
+			for _, tokenID := range revoked {
+				if denylisted, _ := ts.IsTokenRevoked(ctx, tokenID); !denylisted {
+					t.Errorf("IsTokenRevoked(%s) = false, want true", tokenID)
+				}
+			}
+			for _, tokenID := range []string{other.AccessTokenID, other.RefreshTokenID} {
+				if denylisted, _ := ts.IsTokenRevoked(ctx, tokenID); denylisted {
+					t.Errorf("token %s of another family was revoked", tokenID)
+				}
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if token.FamilyID != "family-1" || token.SessionID != "session-1" || token.UserID != 1 {
+				t.Errorf("rotated pair = session %s family %s user %d, want session-1 family-1 user 1", token.SessionID, token.FamilyID, token.UserID)
+			}
+			if session := sessions.sessions["session-1"]; !session.ExpiredAt.Equal(token.RefreshExpiredAt) {
+				t.Errorf("session expires at %v, want %v", session.ExpiredAt, token.RefreshExpiredAt)
+			}
+			if token.RefreshTokenID == tt.refresh || token.AccessToken == "" || token.RefreshToken == "" {
+				t.Errorf("rotated pair = %+v, want a new signed pair", token)
+			}
+			used, _ := tokens.GetTokenByRefreshTokenID(tt.refresh)
+			if used.UsedAt == nil || used.RevokedAt != nil {
+				t.Errorf("exchanged token used_at = %v revoked_at = %v, want used and not revoked", used.UsedAt, used.RevokedAt)
+			}
+
+			// The exchanged refresh token must not work twice.
+			if _, err := ts.RotateToken(ctx, claim); !errors.Is(err, ErrRefreshTokenReused) {
+				t.Errorf("second RotateToken() error = %v, want ErrRefreshTokenReused", err)
+			}
+			if denylisted, _ := ts.IsTokenRevoked(ctx, token.RefreshTokenID); !denylisted {
+				t.Error("pair issued by the rotation survived reuse of its predecessor")
+			}
+			if _, ok := sessions.sessions["session-1"]; ok {
+				t.Error("session survived reuse of its refresh token")
+			}
+		})
+	}
+}
+
+func TestTokenServiceIssueToken(t *testing.T) {
+	ctx := context.Background()
+	tokens := &memoryTokens{}
+	sessions := newMemorySessions()
+	ts := NewTokenService(tokens, newMemoryDenylist(), sessions, nil, nil, testTokenGenerator(), false)
+
+	client := SessionClient{Device: "laptop", UserAgent: "curl/8.0", IP: "203.0.113.7"}
+	first, err := ts.IssueToken(ctx, 1, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ts.IssueToken(ctx, 1, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.SessionID == second.SessionID || first.FamilyID == second.FamilyID {
+		t.Error("two logins share a session or token family")
+	}
+	session, ok := sessions.sessions[first.SessionID]
+	if !ok {
+		t.Fatalf("session %s was not stored", first.SessionID)
+	}
+	if session.UserID != 1 || session.FamilyID != first.FamilyID || !session.ExpiredAt.Equal(first.RefreshExpiredAt) {
+		t.Errorf("session = %+v, want user 1 in family %s", session, first.FamilyID)
+	}
+	if session.Device != client.Device || session.UserAgent != client.UserAgent || session.IP != client.IP {
+		t.Errorf("session client = %s %s %s, want %+v", session.Device, session.UserAgent, session.IP, client)
+	}
+	if len(tokens.tokens) != 2 {
+		t.Errorf("recorded %d pairs, want 2", len(tokens.tokens))
+	}
+}
+
+func TestTokenServiceIssueTokenRoleClaims(t *testing.T) {
+	users := &memoryUsers{users: map[uint]*model.User{1: {ID: 1, RoleID: 2}}}
+	permissions := &stubPermissions{permissions: RolePermissions{"users /users": rightRead}, version: 3}
+
+	for _, embed := range []bool{false, true} {
+		generator := testTokenGenerator()
+		ts := NewTokenService(&memoryTokens{}, newMemoryDenylist(), newMemorySessions(), users, permissions, generator, embed)
+
+		token, err := ts.IssueToken(context.Background(), 1, SessionClient{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		claim, err := generator.ParseToken(token.AccessToken)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !embed {
+			if claim.RoleClaims != nil {
+				t.Errorf("role claims = %+v, want none", *claim.RoleClaims)
+			}
+			continue
+		}
+		want := utils.RoleClaims{RoleID: 2, RoleName: "editor", RightsVersion: 3, Permissions: permissions.permissions.Digest()}
+		if claim.RoleClaims == nil || *claim.RoleClaims != want {
+			t.Errorf("role claims = %+v, want %+v", claim.RoleClaims, want)
+		}
+	}
+}
+
+func TestTokenServiceExpireAccessTokens(t *testing.T) {
+	for _, embed := range []bool{false, true} {
+		ctx := context.Background()
+		tokens := &memoryTokens{}
+		tokens.addToken(1, "session-1", "family-1", "access-1", "refresh-1")
+		ts := NewTokenService(tokens, newMemoryDenylist(), newMemorySessions(), nil, nil, testTokenGenerator(), embed)
+
+		if err := ts.ExpireAccessTokens(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+
+		// Only the access token is denylisted, so the session can refresh
+		// into a token with the current rights.
+		if revoked, _ := ts.IsTokenRevoked(ctx, "access-1"); revoked != embed {
+			t.Errorf("embed %v: IsTokenRevoked(access-1) = %v, want %v", embed, revoked, embed)
+		}
+		if revoked, _ := ts.IsTokenRevoked(ctx, "refresh-1"); revoked {
+			t.Errorf("embed %v: refresh token was revoked", embed)
+		}
+	}
+}
+
+func TestTokenServiceRevokeSession(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    uint
+		sessionID string
+		wantErr   error
+		revoked   []string
+	}{
+		{"own session", 1, "session-1", nil, []string{"access-1", "refresh-1", "access-2", "refresh-2"}},
+		{"session of another user", 2, "session-1", ErrSessionNotFound, nil},
+		{"unknown session", 1, "session-9", ErrSessionNotFound, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tokens := &memoryTokens{}
+			tokens.addToken(1, "session-1", "family-1", "access-1", "refresh-1")
+			tokens.addToken(1, "session-1", "family-1", "access-2", "refresh-2")
+			tokens.addToken(1, "session-2", "family-2", "access-3", "refresh-3")
+			sessions := newMemorySessions()
+			sessions.addSession(1, "session-1", "family-1")
+			sessions.addSession(1, "session-2", "family-2")
+			denylist := newMemoryDenylist()
+			ts := NewTokenService(tokens, denylist, sessions, nil, nil, testTokenGenerator(), false)
+
+			err := ts.RevokeSession(ctx, tt.userID, tt.sessionID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RevokeSession() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if len(denylist.ttls) != len(tt.revoked) {
+				t.Errorf("denylisted %v, want %v", denylist.ttls, tt.revoked)
+			}
+			for _, tokenID := range tt.revoked {
+				if revoked, _ := ts.IsTokenRevoked(ctx, tokenID); !revoked {
+					t.Errorf("IsTokenRevoked(%s) = false, want true", tokenID)
+				}
+			}
+			// Only the revoked session ends; the user stays logged in
+			// everywhere else.
+			if revoked, _ := ts.IsTokenRevoked(ctx, "access-3"); revoked {
+				t.Error("token of another session was revoked")
+			}
+			if _, ok := sessions.sessions["session-2"]; !ok {
+				t.Error("another session of the user was deleted")
+			}
+			if _, ok := sessions.sessions["session-1"]; ok == (tt.wantErr == nil) {
+				t.Errorf("session-1 live = %v, want %v", ok, tt.wantErr != nil)
+			}
+		})
+	}
+}
+
+func TestTokenServiceRevokeOtherSessions(t *testing.T) {
+	ctx := context.Background()
+	tokens := &memoryTokens{}
+	tokens.addToken(1, "session-1", "family-1", "access-1", "refresh-1")
+	tokens.addToken(1, "session-2", "family-2", "access-2", "refresh-2")
+	tokens.addToken(2, "session-3", "family-3", "access-3", "refresh-3")
+	sessions := newMemorySessions()
+	sessions.addSession(1, "session-1", "family-1")
+	sessions.addSession(1, "session-2", "family-2")
+	sessions.addSession(2, "session-3", "family-3")
+	ts := NewTokenService(tokens, newMemoryDenylist(), sessions, nil, nil, testTokenGenerator(), false)
+
+	if err := ts.RevokeOtherSessions(ctx, 1, "session-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	for tokenID, want := range map[string]bool{"access-1": false, "access-2": true, "access-3": false} {
+		if revoked, _ := ts.IsTokenRevoked(ctx, tokenID); revoked != want {
+			t.Errorf("IsTokenRevoked(%s) = %v, want %v", tokenID, revoked, want)
+		}
+	}
+	if len(sessions.sessions) != 2 || sessions.sessions["session-2"].ID != "" {
+		t.Errorf("sessions = %v, want session-1 and session-3", sessions.sessions)
+	}
+}
+
+func TestTokenServiceTouchSession(t *testing.T) {
+	tests := []struct {
+		name      string
+		lastSeen  time.Duration
+		ip        string
+		wantSave  bool
+		wantIP    string
+		sessionID string
+		wantErr   error
+	}{
+		{"recently seen", 10 * time.Second, "203.0.113.7", false, "203.0.113.7", "session-1", nil},
+		{"recently seen without ip", 10 * time.Second, "", false, "203.0.113.7", "session-1", nil},
+		{"seen a while ago", 2 * sessionTouchInterval, "203.0.113.7", true, "203.0.113.7", "session-1", nil},
+		{"new ip", 10 * time.Second, "198.51.100.2", true, "198.51.100.2", "session-1", nil},
+		{"revoked session", 10 * time.Second, "203.0.113.7", false, "", "session-9", ErrSessionNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := newMemorySessions()
+			sessions.addSession(1, "session-1", "family-1")
+			session := sessions.sessions["session-1"]
+			session.IP = "203.0.113.7"
+			session.LastSeenAt = time.Now().Add(-tt.lastSeen)
+			sessions.sessions["session-1"] = session
+			ts := NewTokenService(&memoryTokens{}, newMemoryDenylist(), sessions, nil, nil, testTokenGenerator(), false)
+
+			err := ts.TouchSession(context.Background(), tt.sessionID, tt.ip)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TouchSession() error = %v, want %v", err, tt.wantErr)
+			}
+			if saved := sessions.saves > 0; saved != tt.wantSave {
+				t.Errorf("session written = %v, want %v", saved, tt.wantSave)
+			}
+			if err != nil {
+				return
+			}
+			touched := sessions.sessions["session-1"]
+			if touched.IP != tt.wantIP {
+				t.Errorf("session ip = %s, want %s", touched.IP, tt.wantIP)
+			}
+			if tt.wantSave && time.Since(touched.LastSeenAt) > time.Second {
+				t.Errorf("last seen = %v, want now", touched.LastSeenAt)
+			}
+		})
+	}
+}
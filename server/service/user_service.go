@@ -1,129 +1,731 @@
 package service
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"html"
+	"log"
 	"net/http"
+	"net/mail"
+	"net/url"
+	"strconv"
 	"strings"
 	"tablelink_project/server/model"
 	"tablelink_project/server/repository"
 	"tablelink_project/server/utils"
 	"time"
+	"unicode/utf8"
 
+	"github.com/go-redis/redis/v8"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
+const (
+	defaultUserPageSize = 50
+	maxUserPageSize     = 500
+	maxNameLength       = 255
+	maxEmailLength      = 255
+	passwordResetTTL    = 30 * time.Minute
+)
+
+var (
+	ErrInvalidPageToken  = errors.New("invalid page token")
+	ErrInvalidOrderBy    = errors.New("invalid order_by")
+	ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+	ErrUserNotActive     = errors.New("user is not active")
+	ErrStaleToken        = errors.New("token was issued before the role's rights changed")
+	ErrOwnRoleChange     = errors.New("users cannot change their own role")
+	// ErrImpersonationDenied is returned for targets that cannot be
+	// impersonated: the caller, service accounts, users who are not active
+	// and users whose role has rights the caller's role lacks.
+	ErrImpersonationDenied         = errors.New("user cannot be impersonated")
+	ErrImpersonationWithoutSession = errors.New("impersonation requires a session, sign in with a password or single sign-on")
+)
+
+// LoginResult is the outcome of a password login. Either Token is set, or
+// the user must pass MFA with MFAChallenge first, enrolling TOTP on the way
+// when MFAEnrollmentRequired is set.
+type LoginResult struct {
+	Token                 *model.Token
+	MFAChallenge          string
+	MFAChallengeExpiredAt time.Time
+	MFAEnrollmentRequired bool
+}
+
+type UserListQuery struct {
+	Filter    repository.UserFilter
+	OrderBy   string
+	PageSize  int
+	PageToken string
+}
+
+type UserList struct {
+	Users         []model.User
+	NextPageToken string
+	TotalSize     int64
+}
+
+// userPageToken is the keyset cursor encoded in page tokens. It carries the
+// ordering it was created for so a token cannot be replayed with another
+// order_by.
+type userPageToken struct {
+	OrderBy string `json:"o"`
+	Value   string `json:"v"`
+	ID      uint   `json:"i"`
+}
+
 type UserService interface {
 	CreateUser(model.User) error
-	UpdateUser(*model.User) error
-	DeleteUser(userID int) error
-	LoginCheck(username, password string) (*model.Token, error)
+	UpdateUser(ctx context.Context, actorID uint, userID int, update model.User, paths []string) (*model.User, error)
+	DeleteUser(ctx context.Context, userID int) error
+	LoginCheck(ctx context.Context, email, password string, client SessionClient) (*LoginResult, error)
+	CompleteMFALogin(ctx context.Context, challenge *utils.MFAChallengeClaim, code string, client SessionClient) (*model.Token, error)
+	LoginVerifiedUser(ctx context.Context, user *model.User, client SessionClient) (*LoginResult, error)
 	GetUserByID(userID int) (*model.User, error)
-	GetAllUsers() ([]model.User, error)
-	ValidateRoleRights(userID uint, section, route, method string) error
+	GetAllUsers(query UserListQuery) (*UserList, error)
+	ChangePassword(ctx context.Context, userID uint, currentPassword, newPassword string) error
+	IssuePasswordReset(ctx context.Context, actorID, userID uint) (time.Time, error)
+	CompletePasswordReset(ctx context.Context, resetToken, newPassword string) error
+	UnlockUser(ctx context.Context, userID uint) error
+	Impersonate(ctx context.Context, actorID uint, sessionID string, userID uint, reason string) (string, time.Time, error)
+	ValidateRoleRights(ctx context.Context, userID uint, section, route, method string) error
+	ValidateTokenRights(ctx context.Context, role *utils.RoleClaims, section, route, method string) error
 }
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo          repository.UserRepository
+	roleRepo          repository.RoleRepository
+	passwordResetRepo repository.PasswordResetRepository
+	tokenService      TokenService
+	permissionService PermissionService
+	passwordPolicy    PasswordPolicy
+	loginThrottle     LoginThrottle
+	mfaService        MFAService
+	mailer            utils.Mailer
+	passwordResetURL  string
 }
 
-func NewUserService(userRepo repository.UserRepository) UserService {
+// NewUserService creates a UserService. Password reset tokens are appended
+// to passwordResetURL in the email, like invite tokens are to the invite URL.
+func NewUserService(
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	passwordResetRepo repository.PasswordResetRepository,
+	tokenService TokenService,
+	permissionService PermissionService,
+	passwordPolicy PasswordPolicy,
+	loginThrottle LoginThrottle,
+	mfaService MFAService,
+	mailer utils.Mailer,
+	passwordResetURL string,
+) UserService {
 	return &userService{
-		userRepo: userRepo,
+		userRepo:          userRepo,
+		roleRepo:          roleRepo,
+		passwordResetRepo: passwordResetRepo,
+		tokenService:      tokenService,
+		permissionService: permissionService,
+		passwordPolicy:    passwordPolicy,
+		loginThrottle:     loginThrottle,
+		mfaService:        mfaService,
+		mailer:            mailer,
+		passwordResetURL:  passwordResetURL,
 	}
 }
 
 func (us *userService) CreateUser(user model.User) error {
+	user.Email = html.EscapeString(strings.TrimSpace(user.Email))
+
+	err := us.validatePassword(user.Password, "password", user)
+	if err != nil {
+		return err
+	}
+
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 	user.Password = string(hashedPassword)
-	user.Email = html.EscapeString(strings.TrimSpace(user.Email))
 
 	return us.userRepo.CreateUser(user)
 }
 
-func (us *userService) UpdateUser(user *model.User) error {
-	return us.userRepo.UpdateUser(user)
+// UpdateUser applies the fields of update named by paths to the user on
+// behalf of actorID. Every path is validated before anything is written.
+// The actor's role must cover the rights of the user's role, and of the new
+// role when it changes. Nobody can change their own role.
+func (us *userService) UpdateUser(ctx context.Context, actorID uint, userID int, update model.User, paths []string) (*model.User, error) {
+	user, err := us.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	validationErr := &ValidationError{}
+	if len(paths) == 0 {
+		validationErr.add("update_mask", "at least one field must be updated")
+	}
+
+	fields := map[string]interface{}{}
+	for _, path := range paths {
+		switch path {
+		case "name":
+			name := strings.TrimSpace(update.Name)
+			if name == "" {
+				validationErr.add("user.name", "must not be empty")
+			} else if utf8.RuneCountInString(name) > maxNameLength {
+				validationErr.add("user.name", fmt.Sprintf("must be at most %d characters", maxNameLength))
+			}
+			fields["name"] = name
+		case "email":
+			email := html.EscapeString(strings.TrimSpace(update.Email))
+			address, err := mail.ParseAddress(email)
+			if err != nil || address.Address != email || len(email) > maxEmailLength {
+				validationErr.add("user.email", "must be a valid email address")
+			} else if existing, err := us.userRepo.GetUserByEmail(email); err == nil && existing.ID != uint(userID) {
+				validationErr.add("user.email", "is already in use")
+			}
+			fields["email"] = email
+		case "role_id":
+			if _, err := us.roleRepo.GetRoleByID(int(update.RoleID)); err != nil {
+				validationErr.add("user.role_id", "must reference an existing role")
+			}
+			fields["role_id"] = update.RoleID
+		default:
+			validationErr.add("update_mask", fmt.Sprintf("unknown or read-only field: %s", path))
+		}
+	}
+
+	if err := validationErr.orNil(); err != nil {
+		return nil, err
+	}
+
+	if uint(userID) != actorID {
+		err = checkRoleCovered(ctx, us.userRepo, us.permissionService, actorID, user.RoleID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := fields["role_id"]; ok && update.RoleID != user.RoleID {
+		if uint(userID) == actorID {
+			return nil, ErrOwnRoleChange
+		}
+		err = checkRoleCovered(ctx, us.userRepo, us.permissionService, actorID, update.RoleID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = us.userRepo.UpdateUserFields(userID, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// Access tokens may embed the old role.
+	if roleID, ok := fields["role_id"]; ok && roleID != user.RoleID {
+		err = us.tokenService.ExpireAccessTokens(ctx, uint(userID))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return us.userRepo.GetUserByID(userID)
 }
 
-func (us *userService) DeleteUser(userID int) error {
+// DeleteUser signs the user out everywhere and deletes them. Tokens are
+// revoked first because their rows are deleted along with the user.
+func (us *userService) DeleteUser(ctx context.Context, userID int) error {
+	err := us.tokenService.RevokeUserTokens(ctx, userID)
+	if err != nil {
+		return err
+	}
+
 	return us.userRepo.DeleteUser(userID)
 }
 
-func (us *userService) LoginCheck(email, password string) (*model.Token, error) {
-	var err error
+// LoginCheck verifies the credentials and starts a new session, or returns
+// an MFA challenge when the user has MFA enabled or their role requires it.
+// Failed attempts are counted per account and per client IP, and a
+// ThrottledError is returned without checking the password while either is
+// locked out.
+func (us *userService) LoginCheck(ctx context.Context, email, password string, client SessionClient) (*LoginResult, error) {
+	err := us.loginThrottle.Check(ctx, email, client.IP)
+	if err != nil {
+		return nil, err
+	}
 
 	user, err := us.userRepo.GetUserByEmail(email)
+	// Pending users have not chosen a password yet and service accounts
+	// only authenticate with API keys.
+	if err == nil && (user.Status == model.UserStatusPending || user.Kind == model.UserKindService) {
+		err = ErrUserNotActive
+	}
+	if err == nil {
+		err = verifyPassword(password, user.Password)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, ErrUserNotActive) || errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		if throttleErr := us.loginThrottle.RecordFailure(ctx, email, client.IP); throttleErr != nil {
+			return nil, throttleErr
+		}
+		return nil, err
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	err = verifyPassword(password, user.Password)
+	return us.LoginVerifiedUser(ctx, user, client)
+}
 
-	if err != nil && err == bcrypt.ErrMismatchedHashAndPassword {
+// LoginVerifiedUser continues a login once the user's identity was verified
+// by a password or an external identity provider: it starts a new session,
+// or returns an MFA challenge when MFA applies to the user.
+func (us *userService) LoginVerifiedUser(ctx context.Context, user *model.User, client SessionClient) (*LoginResult, error) {
+	mfaRequired, mfaEnrolled, err := us.mfaService.LoginRequirement(user)
+	if err != nil {
 		return nil, err
 	}
+	if mfaRequired {
+		challenge, expiredAt, err := us.tokenService.IssueMFAChallenge(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{
+			MFAChallenge:          challenge,
+			MFAChallengeExpiredAt: expiredAt,
+			MFAEnrollmentRequired: !mfaEnrolled,
+		}, nil
+	}
+
+	token, err := us.startSession(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{Token: token}, nil
+}
 
-	token, err := utils.GenerateToken(user.ID)
+// CompleteMFALogin exchanges an MFA challenge and a TOTP or recovery code
+// for a new session. Wrong codes count as failed logins.
+func (us *userService) CompleteMFALogin(ctx context.Context, challenge *utils.MFAChallengeClaim, code string, client SessionClient) (*model.Token, error) {
+	user, err := us.userRepo.GetUserByID(int(challenge.UserID))
+	if err != nil {
+		return nil, err
+	}
+	// The user may have been re-invited or turned into a service account
+	// since the challenge was issued.
+	if user.Status != model.UserStatusActive || user.Kind == model.UserKindService {
+		return nil, ErrUserNotActive
+	}
+
+	err = us.loginThrottle.Check(ctx, user.Email, client.IP)
+	if err != nil {
+		return nil, err
+	}
 
+	err = us.mfaService.VerifyTOTP(ctx, user.ID, code)
+	if errors.Is(err, ErrInvalidMFACode) {
+		if throttleErr := us.loginThrottle.RecordFailure(ctx, user.Email, client.IP); throttleErr != nil {
+			return nil, throttleErr
+		}
+		return nil, err
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	user.LastAccess = time.Now()
-	err = us.userRepo.UpdateUser(user)
+	err = us.tokenService.ConsumeMFAChallenge(ctx, challenge)
 	if err != nil {
 		return nil, err
 	}
 
-	return &token, nil
+	return us.startSession(ctx, user, client)
+}
+
+// startSession issues the tokens of a new session once the user passed
+// every login step.
+func (us *userService) startSession(ctx context.Context, user *model.User, client SessionClient) (*model.Token, error) {
+	err := us.loginThrottle.RecordSuccess(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := us.tokenService.IssueToken(ctx, user.ID, client)
+	if err != nil {
+		return nil, err
+	}
+
+	err = us.userRepo.UpdateUserFields(int(user.ID), map[string]interface{}{
+		"last_access": time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
 }
 
 func (us *userService) GetUserByID(userID int) (*model.User, error) {
 	return us.userRepo.GetUserByID(userID)
 }
 
-func (us *userService) GetAllUsers() ([]model.User, error) {
-	return us.userRepo.GetAllUsers()
+func (us *userService) GetAllUsers(query UserListQuery) (*UserList, error) {
+	orderBy := strings.Fields(strings.ToLower(query.OrderBy))
+	page := repository.UserPage{
+		Filter:  query.Filter,
+		OrderBy: "id",
+		Limit:   query.PageSize,
+	}
+	if len(orderBy) > 0 {
+		page.OrderBy = orderBy[0]
+	}
+	if _, ok := repository.UserOrderColumns[page.OrderBy]; !ok || len(orderBy) > 2 {
+		return nil, ErrInvalidOrderBy
+	}
+	if len(orderBy) == 2 {
+		switch orderBy[1] {
+		case "asc":
+		case "desc":
+			page.Descending = true
+		default:
+			return nil, ErrInvalidOrderBy
+		}
+	}
+	normalizedOrderBy := strings.Join(orderBy, " ")
+
+	if page.Limit <= 0 {
+		page.Limit = defaultUserPageSize
+	}
+	if page.Limit > maxUserPageSize {
+		page.Limit = maxUserPageSize
+	}
+
+	if query.PageToken != "" {
+		cursor, err := decodeUserPageToken(query.PageToken)
+		if err != nil || cursor.OrderBy != normalizedOrderBy {
+			return nil, ErrInvalidPageToken
+		}
+		value, err := userCursorValue(cursor, page.OrderBy)
+		if err != nil {
+			return nil, ErrInvalidPageToken
+		}
+		page.HasCursor = true
+		page.AfterValue = value
+		page.AfterID = cursor.ID
+	}
+
+	// Fetch one extra row to learn whether another page follows.
+	page.Limit++
+	users, err := us.userRepo.GetAllUsers(page)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := us.userRepo.CountUsers(query.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &UserList{
+		Users:     users,
+		TotalSize: total,
+	}
+	if len(users) == page.Limit {
+		list.Users = users[:len(users)-1]
+		last := list.Users[len(list.Users)-1]
+		list.NextPageToken = encodeUserPageToken(userPageToken{
+			OrderBy: normalizedOrderBy,
+			Value:   userOrderValue(last, page.OrderBy),
+			ID:      last.ID,
+		})
+	}
+
+	return list, nil
+}
+
+func encodeUserPageToken(token userPageToken) string {
+	encoded, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func decodeUserPageToken(pageToken string) (*userPageToken, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &userPageToken{}
+	err = json.Unmarshal(decoded, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// userOrderValue returns the value the user sorts by as stored in a page
+// token. A NULL last_access is read as the zero time, which is also what the
+// repository coalesces it to.
+func userOrderValue(user model.User, orderBy string) string {
+	switch orderBy {
+	case "email":
+		return user.Email
+	case "name":
+		return user.Name
+	case "last_access":
+		return user.LastAccess.UTC().Format(time.RFC3339Nano)
+	case "created_at":
+		return user.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return strconv.FormatUint(uint64(user.ID), 10)
+}
+
+// userCursorValue converts a page token value back to the Go type of its
+// order column.
+func userCursorValue(token *userPageToken, orderBy string) (interface{}, error) {
+	switch orderBy {
+	case "email", "name":
+		return token.Value, nil
+	case "last_access", "created_at":
+		return time.Parse(time.RFC3339Nano, token.Value)
+	}
+	return token.ID, nil
+}
+
+// ChangePassword replaces the user's password after verifying the current
+// one, then signs the user out everywhere.
+func (us *userService) ChangePassword(ctx context.Context, userID uint, currentPassword, newPassword string) error {
+	user, err := us.userRepo.GetUserByID(int(userID))
+	if err != nil {
+		return err
+	}
+
+	if verifyPassword(currentPassword, user.Password) != nil {
+		validationErr := &ValidationError{}
+		validationErr.add("current_password", "is incorrect")
+		return validationErr
+	}
+
+	return us.setPassword(ctx, *user, newPassword, "new_password")
+}
+
+// IssuePasswordReset emails the user a single-use token that lets them
+// choose a new password until it expires. Only a hash of the token is
+// stored. Callers cannot reset the password of users whose role has rights
+// their own role lacks.
+func (us *userService) IssuePasswordReset(ctx context.Context, actorID, userID uint) (time.Time, error) {
+	user, err := us.userRepo.GetUserByID(int(userID))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if userID != actorID {
+		err = checkRoleCovered(ctx, us.userRepo, us.permissionService, actorID, user.RoleID)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return time.Time{}, err
+	}
+	resetToken := base64.RawURLEncoding.EncodeToString(secret)
+
+	expiredAt := time.Now().Add(passwordResetTTL)
+	err = us.passwordResetRepo.Save(ctx, hashToken(resetToken), userID, passwordResetTTL)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	resetLink := resetToken
+	if us.passwordResetURL != "" {
+		resetLink = us.passwordResetURL + url.QueryEscape(resetToken)
+	}
+	err = us.mailer.Send(utils.Mail{
+		To:      user.Email,
+		Subject: "Reset your tablelink password",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nA password reset was requested for your tablelink account. Choose a new password here:\n\n%s\n\nThe link expires at %s.\n",
+			user.Name, resetLink, expiredAt.UTC().Format(time.RFC1123),
+		),
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return expiredAt, nil
+}
+
+// CompletePasswordReset consumes a reset token and sets the new password,
+// then signs the user out everywhere.
+func (us *userService) CompletePasswordReset(ctx context.Context, resetToken, newPassword string) error {
+	if resetToken == "" {
+		return ErrInvalidResetToken
+	}
+
+	tokenHash := hashToken(resetToken)
+	userID, err := us.passwordResetRepo.Lookup(ctx, tokenHash)
+	if errors.Is(err, redis.Nil) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+
+	user, err := us.userRepo.GetUserByID(int(userID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+
+	// Check the policy before consuming so a rejected password does not
+	// burn the token.
+	err = us.validatePassword(newPassword, "new_password", *user)
+	if err != nil {
+		return err
+	}
+
+	_, err = us.passwordResetRepo.Consume(ctx, tokenHash)
+	if errors.Is(err, redis.Nil) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+
+	return us.setPassword(ctx, *user, newPassword, "new_password")
+}
+
+// UnlockUser clears the failed login attempts of the user's account.
+func (us *userService) UnlockUser(ctx context.Context, userID uint) error {
+	user, err := us.userRepo.GetUserByID(int(userID))
+	if err != nil {
+		return err
+	}
+
+	return us.loginThrottle.Unlock(ctx, user.Email)
+}
+
+// Impersonate issues a short-lived access token that lets the actor act as
+// another user, for example to reproduce what a customer sees. The token
+// belongs to the actor's session and the reason is written to the audit
+// log.
+func (us *userService) Impersonate(ctx context.Context, actorID uint, sessionID string, userID uint, reason string) (string, time.Time, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		validationErr := &ValidationError{}
+		validationErr.add("reason", "must not be empty")
+		return "", time.Time{}, validationErr
+	}
+	if sessionID == "" {
+		return "", time.Time{}, ErrImpersonationWithoutSession
+	}
+
+	user, err := us.userRepo.GetUserByID(int(userID))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if user.ID == actorID || user.Kind == model.UserKindService || user.Status != model.UserStatusActive {
+		return "", time.Time{}, ErrImpersonationDenied
+	}
+
+	// Impersonation must not reach rights the actor does not hold already.
+	err = checkRoleCovered(ctx, us.userRepo, us.permissionService, actorID, user.RoleID)
+	if errors.Is(err, ErrRoleNotCovered) {
+		return "", time.Time{}, ErrImpersonationDenied
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	accessToken, expiredAt, err := us.tokenService.IssueImpersonationToken(ctx, actorID, user.ID, sessionID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	log.Printf("audit: user %d started impersonating user %d until %s: %q", actorID, user.ID, expiredAt.Format(time.RFC3339), reason)
+	return accessToken, expiredAt, nil
+}
+
+func (us *userService) setPassword(ctx context.Context, user model.User, password, field string) error {
+	err := us.validatePassword(password, field, user)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	err = us.userRepo.UpdateUserFields(int(user.ID), map[string]interface{}{
+		"password": string(hashedPassword),
+	})
+	if err != nil {
+		return err
+	}
+
+	return us.tokenService.RevokeUserTokens(ctx, int(user.ID))
+}
+
+func (us *userService) validatePassword(password, field string, user model.User) error {
+	return validatePasswordPolicy(us.passwordPolicy, password, field, user)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
 func verifyPassword(password, hashedPassword string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
 
-func (us *userService) ValidateRoleRights(userID uint, section, route, method string) error {
-	var roleRight model.RoleRight
+func (us *userService) ValidateRoleRights(ctx context.Context, userID uint, section, route, method string) error {
+	roleID, err := us.userRepo.GetUserRoleID(int(userID))
+	if err != nil {
+		return err
+	}
 
-	user, err := us.GetUserByID(int(userID))
+	permissions, err := us.permissionService.GetRolePermissions(ctx, roleID)
 	if err != nil {
 		return err
 	}
 
-	for _, role := range user.Role.RoleRight {
-		if role.Section == section && role.Route == route {
-			roleRight = role
-			break
-		}
+	return checkPermission(permissions, section, route, method)
+}
+
+// ValidateTokenRights checks the rights of the role embedded in an access
+// token against the cached rights of that role, without looking the user
+// up. It returns ErrStaleToken when the role's rights changed since the
+// token was issued. Tokens of users who were deleted or moved to another
+// role are revoked instead, so the denylist and session checks reject them.
+func (us *userService) ValidateTokenRights(ctx context.Context, role *utils.RoleClaims, section, route, method string) error {
+	rights, err := us.permissionService.GetRoleRights(ctx, role.RoleID)
+	if err != nil {
+		return err
+	}
+	if rights.Version != role.RightsVersion {
+		return ErrStaleToken
 	}
 
+	return checkPermission(rights.Permissions, section, route, method)
+}
+
+func checkPermission(permissions RolePermissions, section, route, method string) error {
 	switch method {
-	case http.MethodPost:
-		if roleRight.RCreate != 1 {
-			return errors.New("access denied: POST method not allowed")
-		}
-	case http.MethodGet:
-		if roleRight.RRead != 1 {
-			return errors.New("access denied: GET method not allowed")
-		}
-	case http.MethodPut:
-		if roleRight.RUpdate != 1 {
-			return errors.New("access denied: PUT method not allowed")
-		}
-	case http.MethodDelete:
-		if roleRight.RDelete != 1 {
-			return errors.New("access denied: DELETE method not allowed")
+	case http.MethodPost, http.MethodGet, http.MethodPut, http.MethodDelete:
+		if !permissions.Allows(section, route, method) {
+			return fmt.Errorf("access denied: %s method not allowed", method)
 		}
 	default:
 		return errors.New("access denied: unsupported HTTP method")
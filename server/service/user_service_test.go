@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"tablelink_project/server/model"
+	"tablelink_project/server/utils"
+)
+
+func TestUserPageTokenRoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 10, 18, 9, 30, 15, 123456789, time.FixedZone("WIB", 7*60*60))
+	user := model.User{ID: 42, Email: "jane@example.com", Name: "Jane Doe", CreatedAt: createdAt}
+
+	tests := []struct {
+		orderBy string
+		want    interface{}
+	}{
+		{"id", uint(42)},
+		{"email", "jane@example.com"},
+		{"name", "Jane Doe"},
+		{"created_at", createdAt.UTC()},
+		{"last_access", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.orderBy, func(t *testing.T) {
+			encoded := encodeUserPageToken(userPageToken{
+				OrderBy: tt.orderBy + " desc",
+				Value:   userOrderValue(user, tt.orderBy),
+				ID:      user.ID,
+			})
+
+			token, err := decodeUserPageToken(encoded)
+			if err != nil {
+				t.Fatalf("decodeUserPageToken(%q): %v", encoded, err)
+			}
+			if token.OrderBy != tt.orderBy+" desc" || token.ID != user.ID {
+				t.Errorf("decoded token = %+v", token)
+			}
+
+			value, err := userCursorValue(token, tt.orderBy)
+			if err != nil {
+				t.Fatalf("userCursorValue(): %v", err)
+			}
+			if want, ok := tt.want.(time.Time); ok {
+				if got, _ := value.(time.Time); !got.Equal(want) {
+					t.Errorf("userCursorValue() = %v, want %v", value, want)
+				}
+				return
+			}
+			if value != tt.want {
+				t.Errorf("userCursorValue() = %v (%T), want %v (%T)", value, value, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeUserPageTokenInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		pageToken string
+	}{
+		{"not base64", "!!!"},
+		{"standard padding", base64.StdEncoding.EncodeToString([]byte(`{"o":"id","i":1}`))},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("id:1"))},
+		{"wrong types", base64.RawURLEncoding.EncodeToString([]byte(`{"o":1,"i":"1"}`))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if token, err := decodeUserPageToken(tt.pageToken); err == nil {
+				t.Errorf("decodeUserPageToken(%q) = %+v, want error", tt.pageToken, token)
+			}
+		})
+	}
+}
+
+func TestUserCursorValueInvalidTime(t *testing.T) {
+	token := &userPageToken{OrderBy: "created_at", Value: "yesterday", ID: 1}
+	if _, err := userCursorValue(token, "created_at"); err == nil {
+		t.Error("userCursorValue() accepted a malformed time")
+	}
+}
+
+func TestGetAllUsersRejectsInvalidQuery(t *testing.T) {
+	emailToken := encodeUserPageToken(userPageToken{OrderBy: "email", Value: "jane@example.com", ID: 1})
+	timeToken := encodeUserPageToken(userPageToken{OrderBy: "created_at", Value: "yesterday", ID: 1})
+
+	tests := []struct {
+		name    string
+		query   UserListQuery
+		wantErr error
+	}{
+		{"unknown column", UserListQuery{OrderBy: "password"}, ErrInvalidOrderBy},
+		{"unknown direction", UserListQuery{OrderBy: "email up"}, ErrInvalidOrderBy},
+		{"too many terms", UserListQuery{OrderBy: "email asc name"}, ErrInvalidOrderBy},
+		{"malformed token", UserListQuery{PageToken: "!!!"}, ErrInvalidPageToken},
+		{"token for another order", UserListQuery{OrderBy: "email desc", PageToken: emailToken}, ErrInvalidPageToken},
+		{"token with malformed value", UserListQuery{OrderBy: "created_at", PageToken: timeToken}, ErrInvalidPageToken},
+	}
+
+	// Invalid queries are rejected before the repository is used.
+	us := &userService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := us.GetAllUsers(tt.query); !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetAllUsers() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserServiceValidateTokenRights(t *testing.T) {
+	// Without a user repository, any lookup of the user would panic.
+	us := &userService{permissionService: &stubPermissions{
+		permissions: RolePermissions{"users /users": rightRead},
+		version:     3,
+	}}
+
+	tests := []struct {
+		name    string
+		version int
+		method  string
+		wantErr bool
+		stale   bool
+	}{
+		{"current rights", 3, http.MethodGet, false, false},
+		{"missing right", 3, http.MethodDelete, true, false},
+		{"rights changed", 2, http.MethodGet, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role := &utils.RoleClaims{RoleID: 2, RoleName: "editor", RightsVersion: tt.version}
+			err := us.ValidateTokenRights(context.Background(), role, "users", "/users", tt.method)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateTokenRights() error = %v, want error %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrStaleToken) != tt.stale {
+				t.Errorf("ValidateTokenRights() error = %v, want stale %v", err, tt.stale)
+			}
+		})
+	}
+}
+
+func TestUserServiceImpersonate(t *testing.T) {
+	users := &memoryUsers{users: map[uint]*model.User{
+		1: {ID: 1, RoleID: 1, Kind: model.UserKindHuman, Status: model.UserStatusActive},
+		2: {ID: 2, RoleID: 2, Kind: model.UserKindHuman, Status: model.UserStatusActive},
+		3: {ID: 3, RoleID: 3, Kind: model.UserKindHuman, Status: model.UserStatusActive},
+		4: {ID: 4, RoleID: 2, Kind: model.UserKindService, Status: model.UserStatusActive},
+		5: {ID: 5, RoleID: 2, Kind: model.UserKindHuman, Status: model.UserStatusPending},
+		6: {ID: 6, RoleID: 1, Kind: model.UserKindHuman, Status: model.UserStatusActive},
+	}}
+	permissions := &stubPermissions{roles: map[uint]RolePermissions{
+		1: {"users /users": rightRead | rightDelete, "auth /auth/impersonate": rightCreate},
+		2: {"users /users": rightRead},
+		3: {"roles /roles": rightCreate},
+	}}
+	us := &userService{
+		userRepo:          users,
+		permissionService: permissions,
+		tokenService:      NewTokenService(nil, nil, nil, users, permissions, testTokenGenerator(), false),
+	}
+
+	tests := []struct {
+		name      string
+		sessionID string
+		userID    uint
+		reason    string
+		wantErr   error
+	}{
+		{"narrower role", "session", 2, "ticket 1234", nil},
+		{"same role", "session", 6, "ticket 1234", nil},
+		{"broader role", "session", 3, "ticket 1234", ErrImpersonationDenied},
+		{"self", "session", 1, "ticket 1234", ErrImpersonationDenied},
+		{"service account", "session", 4, "ticket 1234", ErrImpersonationDenied},
+		{"pending user", "session", 5, "ticket 1234", ErrImpersonationDenied},
+		{"without session", "", 2, "ticket 1234", ErrImpersonationWithoutSession},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, _, err := us.Impersonate(context.Background(), 1, tt.sessionID, tt.userID, tt.reason)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Impersonate() error = %v, want %v", err, tt.wantErr)
+			}
+			if (token != "") != (tt.wantErr == nil) {
+				t.Errorf("Impersonate() token = %q, want one only on success", token)
+			}
+		})
+	}
+
+	var validationErr *ValidationError
+	if _, _, err := us.Impersonate(context.Background(), 1, "session", 2, " "); !errors.As(err, &validationErr) {
+		t.Errorf("Impersonate(empty reason) error = %v, want a validation error", err)
+	}
+}
+
+func TestUserServiceUpdateUserRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  int
+		update  model.User
+		paths   []string
+		wantErr error
+	}{
+		{"rename", 2, model.User{Name: "John"}, []string{"name"}, nil},
+		{"role of the caller", 2, model.User{RoleID: 1}, []string{"role_id"}, nil},
+		{"broader role", 2, model.User{RoleID: 3}, []string{"role_id"}, ErrRoleNotCovered},
+		{"user with a broader role", 3, model.User{Name: "Root"}, []string{"name"}, ErrRoleNotCovered},
+		{"rename self", 1, model.User{Name: "Jane"}, []string{"name"}, nil},
+		{"own role", 1, model.User{RoleID: 2}, []string{"role_id"}, ErrOwnRoleChange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &memoryUsers{users: map[uint]*model.User{
+				1: {ID: 1, RoleID: 1},
+				2: {ID: 2, RoleID: 2},
+				3: {ID: 3, RoleID: 3},
+			}}
+			us := &userService{
+				userRepo: users,
+				roleRepo: &memoryRoles{ids: map[uint]bool{1: true, 2: true, 3: true}},
+				permissionService: &stubPermissions{roles: map[uint]RolePermissions{
+					1: {"users /users": rightRead, "users /users/user": rightUpdate},
+					2: {"users /users": rightRead},
+					3: {"users /users": rightRead, "roles /roles": rightCreate},
+				}},
+				tokenService: NewTokenService(&memoryTokens{}, newMemoryDenylist(), newMemorySessions(), nil, nil, testTokenGenerator(), false),
+			}
+			before := *users.users[uint(tt.userID)]
+
+			// User 1 makes every update.
+			_, err := us.UpdateUser(context.Background(), 1, tt.userID, tt.update, tt.paths)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateUser() error = %v, want %v", err, tt.wantErr)
+			}
+			if after := users.users[uint(tt.userID)]; err != nil && (after.Name != before.Name || after.RoleID != before.RoleID) {
+				t.Errorf("refused update changed the user to %+v", after)
+			}
+		})
+	}
+}
+
+// memoryResets is an in-memory PasswordResetRepository that ignores expiry.
+type memoryResets struct {
+	users map[string]uint
+}
+
+func (m *memoryResets) Save(ctx context.Context, tokenHash string, userID uint, ttl time.Duration) error {
+	m.users[tokenHash] = userID
+	return nil
+}
+
+func (m *memoryResets) Lookup(ctx context.Context, tokenHash string) (uint, error) {
+	return m.users[tokenHash], nil
+}
+
+func (m *memoryResets) Consume(ctx context.Context, tokenHash string) (uint, error) {
+	userID := m.users[tokenHash]
+	delete(m.users, tokenHash)
+	return userID, nil
+}
+
+func TestUserServiceIssuePasswordReset(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  uint
+		wantErr error
+	}{
+		{"narrower role", 2, nil},
+		{"self", 1, nil},
+		{"broader role", 3, ErrRoleNotCovered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resets := &memoryResets{users: map[string]uint{}}
+			mailer := utils.NewMemoryMailer()
+			us := &userService{
+				userRepo: &memoryUsers{users: map[uint]*model.User{
+					1: {ID: 1, RoleID: 1, Email: "jane@example.com"},
+					2: {ID: 2, RoleID: 2, Email: "john@example.com"},
+					3: {ID: 3, RoleID: 3, Email: "root@example.com"},
+				}},
+				passwordResetRepo: resets,
+				permissionService: &stubPermissions{roles: map[uint]RolePermissions{
+					1: {"users /users": rightRead | rightUpdate},
+					2: {"users /users": rightRead},
+					3: {"users /users": rightRead, "roles /roles": rightCreate},
+				}},
+				mailer:           mailer,
+				passwordResetURL: "https://app.example.com/reset?token=",
+			}
+
+			// User 1 requests every reset.
+			_, err := us.IssuePasswordReset(context.Background(), 1, tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("IssuePasswordReset() error = %v, want %v", err, tt.wantErr)
+			}
+			sent := mailer.Sent()
+			if err != nil {
+				if len(sent) != 0 || len(resets.users) != 0 {
+					t.Errorf("refused reset sent %d emails and stored %d tokens", len(sent), len(resets.users))
+				}
+				return
+			}
+			if len(sent) != 1 {
+				t.Fatalf("sent %d emails, want 1", len(sent))
+			}
+			if want := us.userRepo.(*memoryUsers).users[tt.userID].Email; sent[0].To != want {
+				t.Errorf("email sent to %q, want %q", sent[0].To, want)
+			}
+			_, link, _ := strings.Cut(sent[0].Body, "?token=")
+			resetToken, _, _ := strings.Cut(link, "\n")
+			if userID, ok := resets.users[hashToken(resetToken)]; !ok || userID != tt.userID {
+				t.Errorf("email does not carry the stored token of user %d", tt.userID)
+			}
+		})
+	}
+}
+
+func TestUserServiceCompleteMFALoginInactiveUser(t *testing.T) {
+	// Without a login throttle, MFA service or token service, the login
+	// must be refused before reaching any of them.
+	us := &userService{userRepo: &memoryUsers{users: map[uint]*model.User{
+		1: {ID: 1, Kind: model.UserKindHuman, Status: model.UserStatusPending},
+		2: {ID: 2, Kind: model.UserKindService, Status: model.UserStatusActive},
+	}}}
+
+	for _, userID := range []uint{1, 2} {
+		challenge := &utils.MFAChallengeClaim{UserID: userID, MFAChallenge: true}
+		_, err := us.CompleteMFALogin(context.Background(), challenge, "123456", SessionClient{})
+		if !errors.Is(err, ErrUserNotActive) {
+			t.Errorf("CompleteMFALogin(user %d) error = %v, want %v", userID, err, ErrUserNotActive)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientIPCtxKey holds the caller's IP address once ResolveClientIP
+// determined it.
+const ClientIPCtxKey ContextKey = "client_ip"
+
+// ClientIP returns the IP address of the caller as resolved for the call,
+// or the peer address when it was not resolved.
+func ClientIP(ctx context.Context) string {
+	if ip, ok := ctx.Value(ClientIPCtxKey).(string); ok {
+		return ip
+	}
+	return peerIP(ctx)
+}
+
+// ResolveClientIP returns the IP address of the caller. Only calls from a
+// trusted proxy such as the gateway may name the client in X-Forwarded-For,
+// and only its last entry, which the proxy appends itself, is used; earlier
+// entries are client supplied. Everyone else, including clients calling
+// the gRPC port directly, is identified by the peer address.
+func ResolveClientIP(ctx context.Context, trustedProxies []*net.IPNet) string {
+	ip := peerIP(ctx)
+	if !isTrustedProxy(ip, trustedProxies) {
+		return ip
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if ok {
+		forwarded := md.Get("x-forwarded-for")
+		if len(forwarded) > 0 {
+			hops := strings.Split(forwarded[len(forwarded)-1], ",")
+			if forwardedIP := strings.TrimSpace(hops[len(hops)-1]); forwardedIP != "" {
+				return forwardedIP
+			}
+		}
+	}
+	return ip
+}
+
+func isTrustedProxy(ip string, trustedProxies []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// UserAgent returns the user agent of the caller, preferring the one the
+// gateway forwards from the HTTP request over its own gRPC user agent.
+func UserAgent(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	for _, key := range []string{"grpcgateway-user-agent", "user-agent"} {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
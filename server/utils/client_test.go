@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestResolveClientIP(t *testing.T) {
+	_, loopback, _ := net.ParseCIDR("127.0.0.1/32")
+	_, internal, _ := net.ParseCIDR("10.0.0.0/8")
+	trusted := []*net.IPNet{loopback, internal}
+
+	tests := []struct {
+		name      string
+		peer      string
+		forwarded []string
+		want      string
+	}{
+		{"direct client without header", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"direct client spoofing header", "203.0.113.7:5000", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"trusted proxy", "127.0.0.1:5000", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"trusted proxy uses last hop", "10.1.2.3:5000", []string{"192.0.2.9, 198.51.100.1"}, "198.51.100.1"},
+		{"trusted proxy uses last header", "10.1.2.3:5000", []string{"192.0.2.9", "198.51.100.1"}, "198.51.100.1"},
+		{"trusted proxy without header", "127.0.0.1:5000", nil, "127.0.0.1"},
+		{"trusted proxy with empty hop", "127.0.0.1:5000", []string{"198.51.100.1, "}, "127.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := net.ResolveTCPAddr("tcp", tt.peer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+			if tt.forwarded != nil {
+				md := metadata.MD{}
+				md.Append("x-forwarded-for", tt.forwarded...)
+				ctx = metadata.NewIncomingContext(ctx, md)
+			}
+
+			if got := ResolveClientIP(ctx, trusted); got != tt.want {
+				t.Errorf("ResolveClientIP() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	addr, _ := net.ResolveTCPAddr("tcp", "203.0.113.7:5000")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+
+	if got := ClientIP(ctx); got != "203.0.113.7" {
+		t.Errorf("ClientIP() without resolved IP = %s, want the peer address", got)
+	}
+
+	ctx = context.WithValue(ctx, ClientIPCtxKey, "198.51.100.1")
+	if got := ClientIP(ctx); got != "198.51.100.1" {
+		t.Errorf("ClientIP() = %s, want the resolved IP", got)
+	}
+}
@@ -0,0 +1,23 @@
+package utils
+
+import "time"
+
+// Clock tells the current time. Code that depends on time takes a Clock so
+// it can be run against a fixed instant.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock always returns the same instant.
+type FixedClock time.Time
+
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
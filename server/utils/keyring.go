@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Keyring holds every key tokens may be verified with and the one key new
+// tokens are signed with. Keys are looked up by the kid token header, so a
+// new key can be promoted while tokens signed by the previous one stay valid
+// until that key is retired.
+type Keyring struct {
+	mu        sync.RWMutex
+	keys      map[string]*SigningKey
+	retired   map[string]bool
+	currentID string
+}
+
+// NewKeyring creates a keyring signing with the key identified by currentID.
+func NewKeyring(keys []*SigningKey, currentID string) (*Keyring, error) {
+	keyring := &Keyring{
+		keys:    map[string]*SigningKey{},
+		retired: map[string]bool{},
+	}
+
+	for _, key := range keys {
+		if _, exists := keyring.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate signing key id: %s", key.ID)
+		}
+		keyring.keys[key.ID] = key
+	}
+
+	if err := keyring.Promote(currentID); err != nil {
+		return nil, err
+	}
+
+	return keyring, nil
+}
+
+// Current returns the key new tokens are signed with.
+func (k *Keyring) Current() *SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.keys[k.currentID]
+}
+
+// VerificationKey returns the non-retired key with the given id.
+func (k *Keyring) VerificationKey(id string) (*SigningKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.keys[id]
+	if !ok || k.retired[id] {
+		return nil, false
+	}
+	return key, true
+}
+
+// VerificationKeys returns every non-retired key.
+func (k *Keyring) VerificationKeys() []*SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	keys := make([]*SigningKey, 0, len(k.keys))
+	for id, key := range k.keys {
+		if !k.retired[id] {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Add registers a verification key without signing with it yet.
+func (k *Keyring) Add(key *SigningKey) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, exists := k.keys[key.ID]; exists {
+		return fmt.Errorf("duplicate signing key id: %s", key.ID)
+	}
+	k.keys[key.ID] = key
+	return nil
+}
+
+// Promote makes the key with the given id the current signing key.
+func (k *Keyring) Promote(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, ok := k.keys[id]; !ok {
+		return fmt.Errorf("unknown signing key id: %s", id)
+	}
+	if k.retired[id] {
+		return fmt.Errorf("signing key %s is retired", id)
+	}
+	k.currentID = id
+	return nil
+}
+
+// Retire stops accepting tokens signed by the key with the given id.
+func (k *Keyring) Retire(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, ok := k.keys[id]; !ok {
+		return fmt.Errorf("unknown signing key id: %s", id)
+	}
+	if id == k.currentID {
+		return fmt.Errorf("cannot retire current signing key %s", id)
+	}
+	k.retired[id] = true
+	return nil
+}
+
+// Replace swaps in the keys of another keyring, used when the key
+// configuration is reloaded.
+func (k *Keyring) Replace(other *Keyring) {
+	other.mu.RLock()
+	keys, retired, currentID := other.keys, other.retired, other.currentID
+	other.mu.RUnlock()
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.keys, k.retired, k.currentID = keys, retired, currentID
+}
@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+func testKeyring(t *testing.T, key *SigningKey) *Keyring {
+	t.Helper()
+
+	ring, err := NewKeyring([]*SigningKey{key}, key.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ring
+}
+
+func TestNewKeyring(t *testing.T) {
+	first := hmacKey(t, "first", jwt.SigningMethodHS256, "first-secret")
+	second := hmacKey(t, "second", jwt.SigningMethodHS256, "second-secret")
+	duplicate := hmacKey(t, "first", jwt.SigningMethodHS256, "other-secret")
+
+	tests := []struct {
+		name      string
+		keys      []*SigningKey
+		currentID string
+		wantErr   bool
+	}{
+		{"single key", []*SigningKey{first}, "first", false},
+		{"several keys", []*SigningKey{first, second}, "second", false},
+		{"duplicate id", []*SigningKey{first, duplicate}, "first", true},
+		{"unknown current", []*SigningKey{first}, "missing", true},
+		{"no keys", nil, "first", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ring, err := NewKeyring(tt.keys, tt.currentID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewKeyring() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && ring.Current().ID != tt.currentID {
+				t.Errorf("Current() = %s, want %s", ring.Current().ID, tt.currentID)
+			}
+		})
+	}
+}
+
+func TestKeyringRetire(t *testing.T) {
+	ring, err := NewKeyring([]*SigningKey{
+		hmacKey(t, "old", jwt.SigningMethodHS256, "old-secret"),
+		hmacKey(t, "new", jwt.SigningMethodHS256, "new-secret"),
+	}, "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ring.Retire("new"); err == nil {
+		t.Error("Retire(current) succeeded, want error")
+	}
+	if err := ring.Retire("missing"); err == nil {
+		t.Error("Retire(unknown) succeeded, want error")
+	}
+	if err := ring.Retire("old"); err != nil {
+		t.Fatalf("Retire(old): %v", err)
+	}
+
+	if _, ok := ring.VerificationKey("old"); ok {
+		t.Error("VerificationKey(old) found a retired key")
+	}
+	if _, ok := ring.VerificationKey("new"); !ok {
+		t.Error("VerificationKey(new) did not find the current key")
+	}
+	if keys := ring.VerificationKeys(); len(keys) != 1 || keys[0].ID != "new" {
+		t.Errorf("VerificationKeys() = %v, want only new", keys)
+	}
+	if err := ring.Promote("old"); err == nil {
+		t.Error("Promote(retired) succeeded, want error")
+	}
+}
+
+func TestJWTKeyRotation(t *testing.T) {
+	ring, err := NewKeyring([]*SigningKey{hmacKey(t, "old", jwt.SigningMethodHS256, "old-secret")}, "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := NewJWT(JWTConfig{Keyring: ring})
+
+	oldToken, err := tokens.Generate(1, nil, "session", "family")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ring.Add(hmacKey(t, "new", jwt.SigningMethodHS256, "new-secret")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ring.Promote("new"); err != nil {
+		t.Fatal(err)
+	}
+
+	newToken, err := tokens.Generate(1, nil, "session", "family")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.ParseToken(newToken.AccessToken); err != nil {
+		t.Errorf("ParseToken(new) after promotion: %v", err)
+	}
+	if _, err := tokens.ParseToken(oldToken.AccessToken); err != nil {
+		t.Errorf("ParseToken(old) after promotion: %v", err)
+	}
+
+	if err := ring.Retire("old"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.ParseToken(oldToken.AccessToken); err == nil {
+		t.Error("ParseToken(old) after retirement succeeded, want error")
+	}
+	if _, err := tokens.ParseToken(newToken.AccessToken); err != nil {
+		t.Errorf("ParseToken(new) after retirement: %v", err)
+	}
+}
+
+func TestJWTParseRejectsForeignKeys(t *testing.T) {
+	ring, err := NewKeyring([]*SigningKey{hmacKey(t, "current", jwt.SigningMethodHS256, "shared-secret")}, "current")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := NewJWT(JWTConfig{Keyring: ring})
+	claims := tokens.createClaims(1, nil, "token", "refresh", "session", time.Now().Add(time.Minute))
+
+	tests := []struct {
+		name    string
+		method  jwt.SigningMethod
+		kid     string
+		secret  string
+		wantErr string
+	}{
+		{"current key", jwt.SigningMethodHS256, "current", "shared-secret", ""},
+		{"missing kid", jwt.SigningMethodHS256, "", "shared-secret", "unknown signing key"},
+		{"unknown kid", jwt.SigningMethodHS256, "other", "shared-secret", "unknown signing key"},
+		{"algorithm mismatch", jwt.SigningMethodHS512, "current", "shared-secret", "unexpected signing method"},
+		{"wrong secret", jwt.SigningMethodHS256, "current", "other-secret", "signature is invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := jwt.NewWithClaims(tt.method, claims)
+			if tt.kid != "" {
+				token.Header["kid"] = tt.kid
+			}
+			signed, err := token.SignedString([]byte(tt.secret))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = tokens.ParseToken(signed)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ParseToken() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseToken() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
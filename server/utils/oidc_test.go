@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// stubIdP is an identity provider serving discovery metadata and a key set
+// holding one RS256 key.
+type stubIdP struct {
+	server *httptest.Server
+	key    *SigningKey
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &stubIdP{
+		key: &SigningKey{
+			ID:        "idp-key",
+			Method:    jwt.SigningMethodRS256,
+			SignKey:   privateKey,
+			VerifyKey: &privateKey.PublicKey,
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcDiscovery{
+			Issuer:                idp.server.URL,
+			AuthorizationEndpoint: idp.server.URL + "/authorize",
+			TokenEndpoint:         idp.server.URL + "/token",
+			JWKSURI:               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		jwk, _ := idp.key.JWK()
+		json.NewEncoder(w).Encode(JWKS{Keys: []JWK{jwk}})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+// claims returns valid ID token claims for the client, nonce and issuer of
+// the stub.
+func (idp *stubIdP) claims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            idp.server.URL,
+		"aud":            "client-id",
+		"sub":            "idp-user-1",
+		"exp":            time.Now().Add(time.Minute).Unix(),
+		"nonce":          "nonce-1",
+		"email":          "jane@example.com",
+		"email_verified": true,
+		"name":           "Jane Doe",
+	}
+}
+
+func (idp *stubIdP) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(idp.key.Method, claims)
+	token.Header["kid"] = idp.key.ID
+	signed, err := token.SignedString(idp.key.SignKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestOIDCProviderVerifyIDToken(t *testing.T) {
+	idp := newStubIdP(t)
+	provider := NewOIDCProvider(OIDCConfig{Issuer: idp.server.URL + "/", ClientID: "client-id"})
+
+	tests := []struct {
+		name    string
+		modify  func(claims jwt.MapClaims)
+		nonce   string
+		wantErr string
+	}{
+		{"valid", func(jwt.MapClaims) {}, "nonce-1", ""},
+		{"audience list", func(c jwt.MapClaims) { c["aud"] = []string{"other", "client-id"} }, "nonce-1", ""},
+		{"email verified as string", func(c jwt.MapClaims) { c["email_verified"] = "true" }, "nonce-1", ""},
+		{"wrong nonce", func(jwt.MapClaims) {}, "nonce-2", "invalid nonce"},
+		{"missing nonce", func(c jwt.MapClaims) { delete(c, "nonce") }, "nonce-1", "invalid nonce"},
+		{"wrong audience", func(c jwt.MapClaims) { c["aud"] = "other" }, "nonce-1", "invalid audience"},
+		{"missing audience", func(c jwt.MapClaims) { delete(c, "aud") }, "nonce-1", "invalid audience"},
+		{"wrong issuer", func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }, "nonce-1", "invalid issuer"},
+		{"expired", func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }, "nonce-1", "expired"},
+		{"no email", func(c jwt.MapClaims) { delete(c, "email") }, "nonce-1", "no email"},
+		{"email not verified", func(c jwt.MapClaims) { c["email_verified"] = false }, "nonce-1", "not verified"},
+		{"email verified as false string", func(c jwt.MapClaims) { c["email_verified"] = "false" }, "nonce-1", "not verified"},
+		{"email verified missing", func(c jwt.MapClaims) { delete(c, "email_verified") }, "nonce-1", "not verified"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := idp.claims()
+			tt.modify(claims)
+
+			identity, err := provider.VerifyIDToken(context.Background(), idp.sign(t, claims), tt.nonce)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("VerifyIDToken() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyIDToken() error = %v", err)
+			}
+			want := OIDCIdentity{Subject: "idp-user-1", Email: "jane@example.com", Name: "Jane Doe"}
+			if *identity != want {
+				t.Errorf("VerifyIDToken() = %+v, want %+v", *identity, want)
+			}
+		})
+	}
+}
+
+func TestOIDCProviderVerifyIDTokenRejectsForeignKeys(t *testing.T) {
+	idp := newStubIdP(t)
+	provider := NewOIDCProvider(OIDCConfig{Issuer: idp.server.URL, ClientID: "client-id"})
+
+	// A token signed with the client secret must not be accepted.
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, idp.claims())
+	hmacToken.Header["kid"] = idp.key.ID
+	signed, err := hmacToken.SignedString([]byte("client-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.VerifyIDToken(context.Background(), signed, "nonce-1"); err == nil || !strings.Contains(err.Error(), "unexpected signing method") {
+		t.Errorf("VerifyIDToken(HS256) error = %v, want unexpected signing method", err)
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreignToken := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.claims())
+	foreignToken.Header["kid"] = "other-key"
+	signed, err = foreignToken.SignedString(otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.VerifyIDToken(context.Background(), signed, "nonce-1"); err == nil || !strings.Contains(err.Error(), "unknown signing key") {
+		t.Errorf("VerifyIDToken(unknown kid) error = %v, want unknown signing key", err)
+	}
+
+	forgedToken := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.claims())
+	forgedToken.Header["kid"] = idp.key.ID
+	signed, err = forgedToken.SignedString(otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.VerifyIDToken(context.Background(), signed, "nonce-1"); err == nil {
+		t.Error("VerifyIDToken(forged signature) succeeded, want error")
+	}
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"tablelink_project/proto/api"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// MethodPermission is the role right required to call a gRPC method, as
+// declared by its (tablelink.permission) option. Sensitive methods are
+// denied to impersonation tokens.
+type MethodPermission struct {
+	Section           string
+	Route             string
+	Method            string
+	AuthenticatedOnly bool
+	Sensitive         bool
+}
+
+var (
+	permissionCache  sync.Map
+	actionHTTPMethod = map[api.Action]string{
+		api.Action_CREATE: http.MethodPost,
+		api.Action_READ:   http.MethodGet,
+		api.Action_UPDATE: http.MethodPut,
+		api.Action_DELETE: http.MethodDelete,
+	}
+)
+
+// LookupPermission resolves the permission of a full gRPC method name such
+// as /user.UserService/GetAllUsers. It reports false when the method has no
+// (tablelink.permission) option.
+func LookupPermission(fullMethod string) (*MethodPermission, bool) {
+	if cached, ok := permissionCache.Load(fullMethod); ok {
+		permission, _ := cached.(*MethodPermission)
+		return permission, permission != nil
+	}
+
+	permission := resolvePermission(fullMethod)
+	permissionCache.Store(fullMethod, permission)
+	return permission, permission != nil
+}
+
+func resolvePermission(fullMethod string) *MethodPermission {
+	serviceName, methodName, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return nil
+	}
+
+	descriptor, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil
+	}
+	service, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil
+	}
+	method := service.Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		return nil
+	}
+
+	options := method.Options()
+	option, ok := proto.GetExtension(options, api.E_Permission).(*api.Permission)
+	if !ok || option == nil {
+		return nil
+	}
+
+	if option.AuthenticatedOnly {
+		return &MethodPermission{AuthenticatedOnly: true, Sensitive: option.Sensitive}
+	}
+
+	httpMethod, exists := actionHTTPMethod[option.Action]
+	if !exists {
+		return nil
+	}
+
+	route := option.Route
+	if route == "" {
+		route = httpRulePath(proto.GetExtension(options, annotations.E_Http).(*annotations.HttpRule))
+	}
+
+	return &MethodPermission{
+		Section:   option.Section,
+		Route:     route,
+		Method:    httpMethod,
+		Sensitive: option.Sensitive,
+	}
+}
+
+func httpRulePath(rule *annotations.HttpRule) string {
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return pattern.Get
+	case *annotations.HttpRule_Post:
+		return pattern.Post
+	case *annotations.HttpRule_Put:
+		return pattern.Put
+	case *annotations.HttpRule_Patch:
+		return pattern.Patch
+	case *annotations.HttpRule_Delete:
+		return pattern.Delete
+	case *annotations.HttpRule_Custom:
+		return pattern.Custom.GetPath()
+	}
+	return ""
+}
+
+// ScopeExists reports whether scope names a registered gRPC service such as
+// user.UserService or one of its methods such as user.UserService/GetUser.
+func ScopeExists(scope string) bool {
+	serviceName, methodName, hasMethod := strings.Cut(scope, "/")
+
+	descriptor, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return false
+	}
+	service, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return false
+	}
+
+	return !hasMethod || service.Methods().ByName(protoreflect.Name(methodName)) != nil
+}
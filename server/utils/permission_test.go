@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+
+	"tablelink_project/proto/api"
+)
+
+func TestLookupPermission(t *testing.T) {
+	tests := []struct {
+		name       string
+		fullMethod string
+		want       *MethodPermission
+	}{
+		{
+			"route from http rule",
+			api.UserService_GetAllUsers_FullMethodName,
+			&MethodPermission{Route: "/users", Method: http.MethodGet},
+		},
+		{
+			"route with path parameter",
+			api.UserService_GetUser_FullMethodName,
+			&MethodPermission{Route: "/users/user/{user_id}", Method: http.MethodGet},
+		},
+		{
+			"create",
+			api.UserService_CreateUser_FullMethodName,
+			&MethodPermission{Route: "/users/user", Method: http.MethodPost},
+		},
+		{
+			"route override",
+			api.UserService_InviteUser_FullMethodName,
+			&MethodPermission{Route: "/users/user", Method: http.MethodPost},
+		},
+		{
+			"update",
+			api.UserService_UpdateUser_FullMethodName,
+			&MethodPermission{Route: "/users/user", Method: http.MethodPut, Sensitive: true},
+		},
+		{
+			"delete",
+			api.UserService_DeleteUser_FullMethodName,
+			&MethodPermission{Route: "/users/user/{user_id}", Method: http.MethodDelete, Sensitive: true},
+		},
+		{
+			"authenticated only",
+			api.UserService_GetMe_FullMethodName,
+			&MethodPermission{AuthenticatedOnly: true},
+		},
+		{
+			"authenticated only self-service",
+			api.UserService_ChangePassword_FullMethodName,
+			&MethodPermission{AuthenticatedOnly: true, Sensitive: true},
+		},
+		{
+			"password reset",
+			api.UserService_ResetPassword_FullMethodName,
+			&MethodPermission{Route: "/users/user/{user_id}/password/reset", Method: http.MethodPut, Sensitive: true},
+		},
+		{
+			"explicit section",
+			api.AuthService_Introspect_FullMethodName,
+			&MethodPermission{Section: "auth", Route: "/auth/introspect", Method: http.MethodGet},
+		},
+		{"public method", api.AuthService_Login_FullMethodName, nil},
+		{"unknown method", "/user.UserService/Missing", nil},
+		{"unknown service", "/user.Missing/GetAllUsers", nil},
+		{"malformed name", "GetAllUsers", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Look up twice so the cached result is checked as well.
+			for i := 0; i < 2; i++ {
+				got, ok := LookupPermission(tt.fullMethod)
+				if ok != (tt.want != nil) {
+					t.Fatalf("LookupPermission(%s) ok = %v, want %v", tt.fullMethod, ok, tt.want != nil)
+				}
+				if tt.want != nil && *got != *tt.want {
+					t.Errorf("LookupPermission(%s) = %+v, want %+v", tt.fullMethod, *got, *tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestScopeExists(t *testing.T) {
+	tests := []struct {
+		scope string
+		want  bool
+	}{
+		{"user.UserService", true},
+		{"user.UserService/GetUser", true},
+		{"user.UserService/Missing", false},
+		{"user.Missing", false},
+		{"user.GetUserRequest", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := ScopeExists(tt.scope); got != tt.want {
+			t.Errorf("ScopeExists(%q) = %v, want %v", tt.scope, got, tt.want)
+		}
+	}
+}
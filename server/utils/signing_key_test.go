@@ -0,0 +1,199 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+)
+
+func hmacKey(t *testing.T, id string, method jwt.SigningMethod, secret string) *SigningKey {
+	t.Helper()
+
+	key, err := NewHMACKey(id, method, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// writePrivateKey stores privateKey as a PKCS #8 PEM file and returns its
+// path.
+func writePrivateKey(t *testing.T, privateKey crypto.PrivateKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewHMACKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  jwt.SigningMethod
+		secret  string
+		wantErr bool
+	}{
+		{"HS256", jwt.SigningMethodHS256, "secret", false},
+		{"HS512", jwt.SigningMethodHS512, "secret", false},
+		{"asymmetric method", jwt.SigningMethodRS256, "secret", true},
+		{"empty secret", jwt.SigningMethodHS256, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := NewHMACKey("kid", tt.method, tt.secret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewHMACKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if _, ok := key.JWK(); ok {
+				t.Error("JWK() published a shared secret")
+			}
+		})
+	}
+}
+
+func TestLoadSigningKeyFile(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		privateKey crypto.PrivateKey
+		method     jwt.SigningMethod
+		kty        string
+		crv        string
+	}{
+		{"RSA", rsaKey, jwt.SigningMethodRS256, "RSA", ""},
+		{"RSA-PSS", rsaKey, jwt.SigningMethodPS256, "RSA", ""},
+		{"ECDSA", ecKey, jwt.SigningMethodES256, "EC", "P-256"},
+		{"EdDSA", edKey, jwt.SigningMethodEdDSA, "OKP", "Ed25519"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writePrivateKey(t, tt.privateKey)
+
+			key, err := LoadSigningKeyFile("", tt.method, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			jwk, ok := key.JWK()
+			if !ok {
+				t.Fatal("JWK() reported no public key")
+			}
+			if jwk.Kty != tt.kty || jwk.Crv != tt.crv || jwk.Alg != tt.method.Alg() || jwk.Use != "sig" {
+				t.Errorf("JWK() = %+v, want kty %s crv %q alg %s", jwk, tt.kty, tt.crv, tt.method.Alg())
+			}
+			if key.ID == "" || key.ID != jwk.Thumbprint() || jwk.Kid != key.ID {
+				t.Errorf("ID = %q, want thumbprint %q", key.ID, jwk.Thumbprint())
+			}
+
+			named, err := LoadSigningKeyFile("named", tt.method, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if named.ID != "named" {
+				t.Errorf("ID = %q, want named", named.ID)
+			}
+		})
+	}
+
+	if _, err := LoadSigningKeyFile("", jwt.SigningMethodRS256, writePrivateKey(t, edKey)); err == nil {
+		t.Error("LoadSigningKeyFile(RS256) of an Ed25519 key succeeded, want error")
+	}
+	if _, err := LoadSigningKeyFile("", jwt.SigningMethodHS256, writePrivateKey(t, rsaKey)); err == nil {
+		t.Error("LoadSigningKeyFile(HS256) succeeded, want error")
+	}
+	if _, err := LoadSigningKeyFile("", jwt.SigningMethodRS256, filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+		t.Error("LoadSigningKeyFile(missing file) succeeded, want error")
+	}
+}
+
+func TestJWKThumbprint(t *testing.T) {
+	// RFC 7638 section 3.1.
+	jwk := JWK{
+		Kty: "RSA",
+		N:   "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		E:   "AQAB",
+		Alg: "RS256",
+		Kid: "2011-04-29",
+	}
+
+	if got, want := jwk.Thumbprint(), "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"; got != want {
+		t.Errorf("Thumbprint() = %s, want %s", got, want)
+	}
+}
+
+func TestJWTAsymmetricKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := LoadSigningKeyFile("rsa", jwt.SigningMethodRS256, writePrivateKey(t, rsaKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := NewJWT(JWTConfig{Keyring: testKeyring(t, key)})
+
+	pair, err := tokens.Generate(7, nil, "session", "family")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.ParseToken(pair.AccessToken); err != nil {
+		t.Errorf("ParseToken(): %v", err)
+	}
+
+	jwks := tokens.JWKS()
+	if len(jwks.Keys) != 1 || jwks.Keys[0].Kid != "rsa" {
+		t.Errorf("JWKS() = %+v, want the rsa key", jwks)
+	}
+
+	// A token signed with HS256 using the public key as secret must not be
+	// accepted in place of an RS256 token.
+	publicDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, tokens.createClaims(7, nil, "forged", "", "session", pair.ExpiredAt))
+	forged.Header["kid"] = "rsa"
+	signed, err := forged.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokens.ParseToken(signed); err == nil {
+		t.Error("ParseToken() accepted an HS256 token signed with the public key")
+	}
+
+	if jwks := NewJWT(JWTConfig{Keyring: testKeyring(t, hmacKey(t, "hmac", jwt.SigningMethodHS256, "secret"))}).JWKS(); len(jwks.Keys) != 0 {
+		t.Errorf("JWKS() = %+v, want no keys for a shared secret", jwks)
+	}
+}
@@ -2,146 +2,392 @@ package utils
 
 import (
 	"fmt"
-	"os"
+	"strconv"
+	"strings"
 	"tablelink_project/server/model"
 	"time"
 
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
 )
 
 type ContextKey string
 
 const (
-	UserCtxKey              ContextKey = "user_id"
-	AccessTokenExpiredTime             = 4 * time.Hour
-	RefreshTokenExpiredTime            = 30 * 24 * time.Hour
-	user_issuer                        = "tablelink_user"
+	UserCtxKey               ContextKey = "user_id"
+	SessionCtxKey            ContextKey = "session_id"
+	MFAChallengeCtxKey       ContextKey = "mfa_challenge"
+	RoleCtxKey               ContextKey = "role"
+	ActorCtxKey              ContextKey = "actor"
+	AccessTokenExpiredTime              = 4 * time.Hour
+	RefreshTokenExpiredTime             = 30 * 24 * time.Hour
+	MFAChallengeExpiredTime             = 5 * time.Minute
+	InviteTokenExpiredTime              = 7 * 24 * time.Hour
+	ImpersonationExpiredTime            = 15 * time.Minute
+	user_issuer                         = "tablelink_user"
+	bearer                              = "Bearer "
+	// ClaimsVersion is the version of the access and refresh token claim
+	// schema, carried as ver. Tokens without ver predate versioning.
+	// Version 3 added act, which servers understanding only version 2
+	// would ignore.
+	ClaimsVersion = 3
 )
 
+// CredentialTokenGenerator is responsible for managing credential tokens.
+type CredentialTokenGenerator interface {
+	// Generate generates an access/refresh pair for the user in the given
+	// session and token family, embedding role in the access token when it
+	// is not nil.
+	Generate(userID uint, role *RoleClaims, sessionID, familyID string) (*model.Token, error)
+	// ParseToken parses and validates an access token.
+	ParseToken(accessToken string) (*UserClaim, error)
+	// ParseRefreshToken parses and validates a refresh token.
+	ParseRefreshToken(refreshToken string) (*RefreshTokenClaim, error)
+	// GenerateMFAChallenge generates the token a user exchanges for
+	// credentials once their second factor is verified.
+	GenerateMFAChallenge(userID uint) (string, time.Time, error)
+	// ParseMFAChallenge parses and validates an MFA challenge token.
+	ParseMFAChallenge(challengeToken string) (*MFAChallengeClaim, error)
+	// GenerateInvite generates the token an invited user activates their
+	// account with.
+	GenerateInvite(userID uint, invitedAt time.Time) (string, time.Time, error)
+	// ParseInvite parses and validates an invite token.
+	ParseInvite(inviteToken string) (*InviteClaim, error)
+	// GenerateImpersonation generates a short-lived access token for the
+	// user in the actor's session, naming the actor in its act claim.
+	GenerateImpersonation(userID uint, role *RoleClaims, actorID uint, sessionID string) (string, time.Time, error)
+	// JWKS returns the public keys tokens can be verified with.
+	JWKS() JWKS
+}
+
+// UserClaim defines JWT access token claims.
 type UserClaim struct {
-	UserID       uint `json:"user_id"`
-	RefreshToken bool `json:"rt"`
+	Version        int         `json:"ver,omitempty"`
+	UserID         uint        `json:"user_id"`
+	RefreshToken   bool        `json:"rt"`
+	RefreshTokenID string      `json:"rti,omitempty"`
+	SessionID      string      `json:"sid,omitempty"`
+	MFAChallenge   bool        `json:"mfa,omitempty"`
+	Invite         bool        `json:"inv,omitempty"`
+	Actor          *ActorClaim `json:"act,omitempty"`
+	*RoleClaims
 	jwt.StandardClaims
 }
 
+// ActorClaim names the user acting through an impersonation token, as the
+// act claim of RFC 8693. The token's own subject is the impersonated user.
+type ActorClaim struct {
+	Subject string `json:"sub"`
+}
+
+// RoleClaims is the role optionally embedded in access tokens, so requests
+// can be authorized against the cached rights of the role without loading
+// the user. Permissions is a digest of the role's rights and RightsVersion
+// tells whether they changed since the token was issued.
+type RoleClaims struct {
+	RoleID        uint   `json:"role_id"`
+	RoleName      string `json:"role"`
+	RightsVersion int    `json:"rights_version"`
+	Permissions   string `json:"perms"`
+}
+
+// RefreshTokenClaim defines JWT refresh token claims.
 type RefreshTokenClaim struct {
+	Version      int    `json:"ver,omitempty"`
+	UserID       uint   `json:"user_id"`
+	RefreshToken bool   `json:"rt"`
+	FamilyID     string `json:"fam,omitempty"`
+	SessionID    string `json:"sid,omitempty"`
+	jwt.StandardClaims
+}
+
+// MFAChallengeClaim defines JWT MFA challenge token claims. A challenge
+// proves the password was verified and is only accepted by the MFA RPCs.
+type MFAChallengeClaim struct {
 	UserID       uint `json:"user_id"`
-	RefreshToken bool `json:"rt"`
+	MFAChallenge bool `json:"mfa"`
 	jwt.StandardClaims
 }
 
-func GenerateToken(user_id uint) (model.Token, error) {
-	expiredAt := time.Now().Add(AccessTokenExpiredTime)
-	accessToken, err := GenerateAccessToken(user_id, expiredAt)
+// InviteClaim defines JWT invite token claims. InvitedAt ties the token to
+// one invitation so re-inviting a user invalidates earlier tokens.
+type InviteClaim struct {
+	UserID    uint  `json:"user_id"`
+	Invite    bool  `json:"inv"`
+	InvitedAt int64 `json:"inv_at"`
+	jwt.StandardClaims
+}
+
+// JWTConfig configures how JWT signs and verifies tokens. Zero values fall
+// back to the tablelink_user issuer and the default lifetimes.
+type JWTConfig struct {
+	Keyring              *Keyring
+	Issuer               string
+	Audience             string
+	AccessTokenLifetime  time.Duration
+	RefreshTokenLifetime time.Duration
+}
+
+// JWT holds the job to generate token using JWT.
+type JWT struct {
+	config JWTConfig
+}
+
+// NewJWT creates an instance of JWT.
+func NewJWT(config JWTConfig) *JWT {
+	if config.Issuer == "" {
+		config.Issuer = user_issuer
+	}
+	if config.AccessTokenLifetime == 0 {
+		config.AccessTokenLifetime = AccessTokenExpiredTime
+	}
+	if config.RefreshTokenLifetime == 0 {
+		config.RefreshTokenLifetime = RefreshTokenExpiredTime
+	}
+
+	return &JWT{
+		config: config,
+	}
+}
+
+// Generate creates an access/refresh pair belonging to the given session
+// and token family. Every pair minted by rotating a refresh token stays in
+// the session and family of the login that started it.
+func (j *JWT) Generate(userID uint, role *RoleClaims, sessionID, familyID string) (*model.Token, error) {
+	accessTokenID := uuid.NewString()
+	refreshTokenID := uuid.NewString()
+
+	expiredAt := time.Now().Add(j.config.AccessTokenLifetime)
+	accessToken, err := j.sign(j.createClaims(userID, role, accessTokenID, refreshTokenID, sessionID, expiredAt))
 	if err != nil {
-		return model.Token{}, err
+		return nil, errors.Wrap(err, "[JWT-Generate] error creating access token")
 	}
 
-	refreshExpiredAt := time.Now().Add(RefreshTokenExpiredTime)
-	refreshToken, err := GenerateRefreshToken(user_id, refreshExpiredAt)
+	refreshExpiredAt := time.Now().Add(j.config.RefreshTokenLifetime)
+	refreshToken, err := j.sign(j.createRefreshClaims(userID, refreshTokenID, sessionID, familyID, refreshExpiredAt))
 	if err != nil {
-		return model.Token{}, err
+		return nil, errors.Wrap(err, "[JWT-Generate] error creating refresh token")
 	}
 
-	return model.Token{
-		UserID:       user_id,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiredAt:    expiredAt,
+	return &model.Token{
+		UserID:           userID,
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		AccessTokenID:    accessTokenID,
+		RefreshTokenID:   refreshTokenID,
+		FamilyID:         familyID,
+		SessionID:        sessionID,
+		ExpiredAt:        expiredAt,
+		RefreshExpiredAt: refreshExpiredAt,
 	}, nil
 }
 
-func GenerateAccessToken(user_id uint, expiredAt time.Time) (string, error) {
-	claims := createClaims(user_id, expiredAt)
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+// ParseToken parses and validates an access token.
+func (j *JWT) ParseToken(accessToken string) (*UserClaim, error) {
+	claim := &UserClaim{}
+	err := j.parse(accessToken, claim)
+	if err != nil {
+		return nil, errors.Wrap(err, "JWT-ParseToken")
+	}
 
-	return token.SignedString([]byte(os.Getenv("API_SECRET")))
+	if claim.RefreshToken || claim.MFAChallenge || claim.Invite {
+		return nil, errors.Wrap(errors.New("invalid token"), "JWT-ParseToken")
+	}
+	if claim.Version > ClaimsVersion {
+		return nil, errors.Wrap(fmt.Errorf("unsupported claims version: %d", claim.Version), "JWT-ParseToken")
+	}
 
+	return claim, nil
 }
 
-func createClaims(user_id uint, expiredAt time.Time) jwt.Claims {
-	return &UserClaim{
-		UserID:       user_id,
-		RefreshToken: false,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expiredAt.Unix(),
-			IssuedAt:  time.Now().Unix(),
-			Issuer:    user_issuer,
-		},
+// ParseRefreshToken parses and validates a refresh token.
+func (j *JWT) ParseRefreshToken(refreshToken string) (*RefreshTokenClaim, error) {
+	claim := &RefreshTokenClaim{}
+	err := j.parse(refreshToken, claim)
+	if err != nil {
+		return nil, errors.Wrap(err, "JWT-ParseRefreshToken")
 	}
-}
 
-func GenerateRefreshToken(user_id uint, expiredAt time.Time) (string, error) {
-	claims := createRefreshClaims(user_id, expiredAt)
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if !claim.RefreshToken {
+		return nil, errors.Wrap(errors.New("invalid token"), "JWT-ParseRefreshToken")
+	}
+	if claim.Version > ClaimsVersion {
+		return nil, errors.Wrap(fmt.Errorf("unsupported claims version: %d", claim.Version), "JWT-ParseRefreshToken")
+	}
+
+	return claim, nil
+}
 
-	return token.SignedString([]byte(os.Getenv("API_SECRET")))
+// GenerateMFAChallenge creates a short-lived MFA challenge token.
+func (j *JWT) GenerateMFAChallenge(userID uint) (string, time.Time, error) {
+	expiredAt := time.Now().Add(MFAChallengeExpiredTime)
+	challengeToken, err := j.sign(&MFAChallengeClaim{
+		UserID:         userID,
+		MFAChallenge:   true,
+		StandardClaims: j.standardClaims(uuid.NewString(), expiredAt),
+	})
+	if err != nil {
+		return "", time.Time{}, errors.Wrap(err, "[JWT-GenerateMFAChallenge] error creating challenge token")
+	}
 
+	return challengeToken, expiredAt, nil
 }
 
-func createRefreshClaims(user_id uint, expiredAt time.Time) jwt.Claims {
-	return &RefreshTokenClaim{
-		UserID:       user_id,
-		RefreshToken: true,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expiredAt.Unix(),
-			IssuedAt:  time.Now().Unix(),
-			Issuer:    user_issuer,
-		},
+// ParseMFAChallenge parses and validates an MFA challenge token.
+func (j *JWT) ParseMFAChallenge(challengeToken string) (*MFAChallengeClaim, error) {
+	claim := &MFAChallengeClaim{}
+	err := j.parse(challengeToken, claim)
+	if err != nil {
+		return nil, errors.Wrap(err, "JWT-ParseMFAChallenge")
+	}
+
+	if !claim.MFAChallenge {
+		return nil, errors.Wrap(errors.New("invalid token"), "JWT-ParseMFAChallenge")
 	}
+
+	return claim, nil
 }
 
-func ValidateToken(tokenString string) (*UserClaim, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(os.Getenv("API_SECRET")), nil
+// GenerateInvite creates an invite token for the invitation sent at
+// invitedAt.
+func (j *JWT) GenerateInvite(userID uint, invitedAt time.Time) (string, time.Time, error) {
+	expiredAt := invitedAt.Add(InviteTokenExpiredTime)
+	inviteToken, err := j.sign(&InviteClaim{
+		UserID:         userID,
+		Invite:         true,
+		InvitedAt:      invitedAt.Unix(),
+		StandardClaims: j.standardClaims(uuid.NewString(), expiredAt),
 	})
 	if err != nil {
-		return nil, err
+		return "", time.Time{}, errors.Wrap(err, "[JWT-GenerateInvite] error creating invite token")
 	}
 
-	claim, ok := token.Claims.(*UserClaim)
-	if !ok {
-		return nil, fmt.Errorf("invalid token")
+	return inviteToken, expiredAt, nil
+}
+
+// ParseInvite parses and validates an invite token.
+func (j *JWT) ParseInvite(inviteToken string) (*InviteClaim, error) {
+	claim := &InviteClaim{}
+	err := j.parse(inviteToken, claim)
+	if err != nil {
+		return nil, errors.Wrap(err, "JWT-ParseInvite")
+	}
+
+	if !claim.Invite {
+		return nil, errors.Wrap(errors.New("invalid token"), "JWT-ParseInvite")
 	}
 
 	return claim, nil
 }
 
-func ValidateRefreshToken(tokenString string) (*RefreshTokenClaim, error) {
-	claim, token, err := ParseRefreshToken(tokenString)
+// GenerateImpersonation creates an access token for userID on behalf of
+// actorID. It has no refresh token and belongs to the actor's session, so
+// it stops working when that session ends.
+func (j *JWT) GenerateImpersonation(userID uint, role *RoleClaims, actorID uint, sessionID string) (string, time.Time, error) {
+	expiredAt := time.Now().Add(ImpersonationExpiredTime)
+	claims := j.createClaims(userID, role, uuid.NewString(), "", sessionID, expiredAt)
+	claims.Actor = &ActorClaim{Subject: strconv.FormatUint(uint64(actorID), 10)}
+
+	accessToken, err := j.sign(claims)
 	if err != nil {
-		return nil, err
+		return "", time.Time{}, errors.Wrap(err, "[JWT-GenerateImpersonation] error creating access token")
 	}
 
-	if !token.Valid {
-		return nil, fmt.Errorf("invalid token")
+	return accessToken, expiredAt, nil
+}
+
+func (j *JWT) createClaims(userID uint, role *RoleClaims, tokenID, refreshTokenID, sessionID string, expiredAt time.Time) *UserClaim {
+	claims := &UserClaim{
+		Version:        ClaimsVersion,
+		UserID:         userID,
+		RefreshToken:   false,
+		RefreshTokenID: refreshTokenID,
+		SessionID:      sessionID,
+		RoleClaims:     role,
+		StandardClaims: j.standardClaims(tokenID, expiredAt),
 	}
+	claims.Subject = strconv.FormatUint(uint64(userID), 10)
+	return claims
+}
 
-	if !claim.RefreshToken {
-		return nil, fmt.Errorf("invalid token")
+func (j *JWT) createRefreshClaims(userID uint, tokenID, sessionID, familyID string, expiredAt time.Time) jwt.Claims {
+	claims := &RefreshTokenClaim{
+		Version:        ClaimsVersion,
+		UserID:         userID,
+		RefreshToken:   true,
+		FamilyID:       familyID,
+		SessionID:      sessionID,
+		StandardClaims: j.standardClaims(tokenID, expiredAt),
 	}
+	claims.Subject = strconv.FormatUint(uint64(userID), 10)
+	return claims
+}
 
-	return claim, nil
+func (j *JWT) standardClaims(tokenID string, expiredAt time.Time) jwt.StandardClaims {
+	return jwt.StandardClaims{
+		Id:        tokenID,
+		Audience:  j.config.Audience,
+		ExpiresAt: expiredAt.Unix(),
+		IssuedAt:  time.Now().Unix(),
+		Issuer:    j.config.Issuer,
+	}
 }
 
-func ParseRefreshToken(tokenString string) (*RefreshTokenClaim, *jwt.Token, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+// JWKS returns the public half of every non-retired key as a JSON Web Key Set.
+func (j *JWT) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for _, key := range j.config.Keyring.VerificationKeys() {
+		if jwk, ok := key.JWK(); ok {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+	return jwks
+}
+
+func (j *JWT) sign(claims jwt.Claims) (string, error) {
+	key := j.config.Keyring.Current()
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.SignKey)
+}
+
+type verifiableClaims interface {
+	jwt.Claims
+	VerifyIssuer(cmp string, req bool) bool
+	VerifyAudience(cmp string, req bool) bool
+}
+
+func (j *JWT) parse(signedToken string, claim verifiableClaims) error {
+	token, err := jwt.ParseWithClaims(TrimBearer(signedToken), claim, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := j.config.Keyring.VerificationKey(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key: %s", kid)
+		}
+		if token.Method.Alg() != key.Method.Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(os.Getenv("API_SECRET")), nil
+		return key.VerifyKey, nil
 	})
 	if err != nil {
-		return nil, nil, err
+		return err
 	}
 
-	claim, ok := token.Claims.(*RefreshTokenClaim)
-	if !ok {
-		return nil, nil, fmt.Errorf("invalid token")
+	if !token.Valid {
+		return errors.New("invalid token")
 	}
+	if !claim.VerifyIssuer(j.config.Issuer, true) {
+		return errors.New("invalid token issuer")
+	}
+	if j.config.Audience != "" && !claim.VerifyAudience(j.config.Audience, true) {
+		return errors.New("invalid token audience")
+	}
+
+	return nil
+}
 
-	return claim, token, nil
+// TrimBearer strips the optional "Bearer " scheme from an authorization value.
+func TrimBearer(token string) string {
+	return strings.TrimPrefix(token, bearer)
 }
@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+func TestJWTGenerate(t *testing.T) {
+	tokens := NewJWT(JWTConfig{Keyring: testKeyring(t, hmacKey(t, "test", jwt.SigningMethodHS256, "test-secret")), AccessTokenLifetime: time.Minute})
+
+	pair, err := tokens.Generate(7, nil, "session", "family")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claim, err := tokens.ParseToken(pair.AccessToken)
+	if err != nil {
+		t.Fatalf("ParseToken(): %v", err)
+	}
+	if claim.UserID != 7 || claim.Id != pair.AccessTokenID || claim.RefreshTokenID != pair.RefreshTokenID || claim.SessionID != "session" {
+		t.Errorf("access claim = %+v, want user 7 in session with the pair's token ids", claim)
+	}
+	if claim.Version != ClaimsVersion || claim.RoleClaims != nil {
+		t.Errorf("access claim version %d with role %+v, want version %d without role", claim.Version, claim.RoleClaims, ClaimsVersion)
+	}
+	if claim.Issuer != user_issuer {
+		t.Errorf("issuer = %s, want the default %s", claim.Issuer, user_issuer)
+	}
+	if lifetime := time.Until(time.Unix(claim.ExpiresAt, 0)); lifetime > time.Minute || lifetime < 58*time.Second {
+		t.Errorf("access token lifetime = %s, want the configured minute", lifetime)
+	}
+
+	refreshClaim, err := tokens.ParseRefreshToken(pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("ParseRefreshToken(): %v", err)
+	}
+	if refreshClaim.Id != pair.RefreshTokenID || refreshClaim.FamilyID != "family" || refreshClaim.SessionID != "session" {
+		t.Errorf("refresh claim = %+v, want the pair's refresh id in session and family", refreshClaim)
+	}
+	if lifetime := time.Until(time.Unix(refreshClaim.ExpiresAt, 0)); lifetime < RefreshTokenExpiredTime-time.Minute {
+		t.Errorf("refresh token lifetime = %s, want the default %s", lifetime, RefreshTokenExpiredTime)
+	}
+
+	role := &RoleClaims{RoleID: 2, RoleName: "editor", RightsVersion: 3, Permissions: "digest"}
+	pair, err = tokens.Generate(7, role, "session", "family")
+	if err != nil {
+		t.Fatal(err)
+	}
+	claim, err = tokens.ParseToken(pair.AccessToken)
+	if err != nil {
+		t.Fatalf("ParseToken(): %v", err)
+	}
+	if claim.RoleClaims == nil || *claim.RoleClaims != *role {
+		t.Errorf("role claims = %+v, want %+v", claim.RoleClaims, *role)
+	}
+}
+
+func TestJWTParseToken(t *testing.T) {
+	key := hmacKey(t, "test", jwt.SigningMethodHS256, "test-secret")
+	tokens := NewJWT(JWTConfig{Keyring: testKeyring(t, key), Issuer: "issuer", Audience: "audience"})
+	pair, err := tokens.Generate(7, nil, "session", "family")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generate := func(config JWTConfig) string {
+		t.Helper()
+
+		other, err := NewJWT(config).Generate(7, nil, "session", "family")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return other.AccessToken
+	}
+	sign := func(method jwt.SigningMethod, claims jwt.Claims) string {
+		t.Helper()
+
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = key.ID
+		signed, err := token.SignedString([]byte("test-secret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	expired := &UserClaim{UserID: 7, StandardClaims: jwt.StandardClaims{
+		Id: "expired", Issuer: "issuer", Audience: "audience", ExpiresAt: time.Now().Add(-time.Minute).Unix(),
+	}}
+	valid := &UserClaim{UserID: 7, StandardClaims: jwt.StandardClaims{
+		Id: "valid", Issuer: "issuer", Audience: "audience", ExpiresAt: time.Now().Add(time.Minute).Unix(),
+	}}
+	newer := &UserClaim{UserID: 7, Version: ClaimsVersion + 1, StandardClaims: valid.StandardClaims}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{"access token", pair.AccessToken, ""},
+		{"bearer scheme", "Bearer " + pair.AccessToken, ""},
+		{"refresh token", pair.RefreshToken, "invalid token"},
+		{"other secret", generate(JWTConfig{Keyring: testKeyring(t, hmacKey(t, "test", jwt.SigningMethodHS256, "other-secret")), Issuer: "issuer", Audience: "audience"}), "signature is invalid"},
+		{"other issuer", generate(JWTConfig{Keyring: testKeyring(t, key), Audience: "audience"}), "invalid token issuer"},
+		{"other audience", generate(JWTConfig{Keyring: testKeyring(t, key), Issuer: "issuer", Audience: "other"}), "invalid token audience"},
+		{"no audience", generate(JWTConfig{Keyring: testKeyring(t, key), Issuer: "issuer"}), "invalid token audience"},
+		{"other algorithm", sign(jwt.SigningMethodHS512, valid), "unexpected signing method"},
+		{"expired", sign(jwt.SigningMethodHS256, expired), "expired"},
+		{"unversioned claims", sign(jwt.SigningMethodHS256, valid), ""},
+		{"newer claims version", sign(jwt.SigningMethodHS256, newer), "unsupported claims version"},
+		{"other key id", generate(JWTConfig{Keyring: testKeyring(t, hmacKey(t, "other", jwt.SigningMethodHS256, "test-secret")), Issuer: "issuer", Audience: "audience"}), "unknown signing key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tokens.ParseToken(tt.token)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ParseToken() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseToken() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := tokens.ParseRefreshToken(pair.AccessToken); err == nil {
+		t.Error("ParseRefreshToken(access token) succeeded, want error")
+	}
+}
+
+func TestTrimBearer(t *testing.T) {
+	tests := map[string]string{
+		"Bearer abc": "abc",
+		"abc":        "abc",
+		"bearer abc": "bearer abc",
+		"":           "",
+	}
+
+	for token, want := range tests {
+		if got := TrimBearer(token); got != want {
+			t.Errorf("TrimBearer(%q) = %q, want %q", token, got, want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 4226 and RFC 6238 test vectors.
+var rfcSecret = []byte("12345678901234567890")
+
+func TestHOTP(t *testing.T) {
+	// RFC 4226 appendix D.
+	expected := []string{
+		"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489",
+	}
+
+	for counter, code := range expected {
+		if got := hotp(rfcSecret, int64(counter)); got != code {
+			t.Errorf("hotp(%d) = %s, want %s", counter, got, code)
+		}
+	}
+}
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 appendix B, SHA-1, truncated to the last 6 of 8 digits.
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	secret := base32.StdEncoding.EncodeToString(rfcSecret)
+	totp := NewTOTP(SystemClock{})
+	for _, tt := range tests {
+		code, err := totp.Code(secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("Code(%d): %v", tt.unix, err)
+		}
+		if code != tt.code {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, code, tt.code)
+		}
+	}
+}
+
+func TestTOTPValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	totp := NewTOTP(FixedClock(now))
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codeAt := func(at time.Time) string {
+		code, err := totp.Code(secret, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name    string
+		secret  string
+		code    string
+		valid   bool
+		counter int64
+	}{
+		{"current period", secret, codeAt(now), true, totpCounter(now)},
+		{"previous period", secret, codeAt(now.Add(-totpPeriod)), true, totpCounter(now) - 1},
+		{"next period", secret, codeAt(now.Add(totpPeriod)), true, totpCounter(now) + 1},
+		{"two periods ago", secret, codeAt(now.Add(-2 * totpPeriod)), false, 0},
+		{"two periods ahead", secret, codeAt(now.Add(2 * totpPeriod)), false, 0},
+		{"lowercase padded secret", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq====", "050471", true, totpCounter(now)},
+		{"wrong length", secret, "12345", false, 0},
+		{"invalid secret", "not base32!", "050471", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter, valid := totp.Validate(tt.secret, tt.code)
+			if valid != tt.valid || counter != tt.counter {
+				t.Errorf("Validate() = (%d, %v), want (%d, %v)", counter, valid, tt.counter, tt.valid)
+			}
+		})
+	}
+}